@@ -3,22 +3,27 @@ package app
 import (
 	"app/config"
 	"app/lib"
+	"app/lib/auth"
 	"app/lib/cache"
 	"app/lib/mailer"
+	"app/lib/oidc"
+	"app/lib/sms"
 	"app/lib/storage"
 	"app/lib/task"
 	"app/lib/websocket"
 	"app/repository"
 	"app/usecase"
+
+	"github.com/go-webauthn/webauthn/webauthn"
 )
 
 type App struct {
 	Usecase *usecase.Usecase
 }
 
-func NewApp(config *config.Config, db *lib.Database, mailer *mailer.SMTP, storage storage.Storage, cache *cache.Cache, publisher *task.Publisher, wsPool *websocket.WebsocketPool) *App {
-	repository := repository.NewRepository(config, db, mailer, publisher, cache, wsPool)
-	usecase := usecase.NewUsecase(config, &repository, storage)
+func NewApp(config *config.Config, db *lib.Database, mailer *mailer.SMTP, smsSender sms.Sender, storage storage.Storage, cache *cache.Cache, publisher *task.Publisher, wsPool *websocket.WebsocketPool, oidcProviders oidc.Providers, keySet *auth.KeySet, webAuthn *webauthn.WebAuthn) *App {
+	repository := repository.NewRepository(config, db, mailer, smsSender, publisher, cache, wsPool, oidcProviders)
+	usecase := usecase.NewUsecase(config, &repository, storage, keySet, webAuthn)
 
 	return &App{
 		Usecase: &usecase,
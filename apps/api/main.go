@@ -4,6 +4,7 @@ import (
 	"app"
 	"app/config"
 	"app/handler"
+	"app/lib/constant"
 	"app/lib/logger"
 	"context"
 	"fmt"
@@ -34,7 +35,12 @@ func main() {
 	if err != nil {
 		log.Fatal("failed connect to database: ", err)
 	}
+	keySet, err := cfg.NewIDTokenKeySet()
+	if err != nil {
+		log.Fatal("failed load id token keys: ", err)
+	}
 	mailer := cfg.NewSMTP()
+	smsSender := cfg.NewSmsSender()
 	storage := cfg.NewStorage()
 	cache, err := cfg.NewCache()
 	if err != nil {
@@ -44,6 +50,11 @@ func main() {
 	if err != nil {
 		log.Fatal("failed connect to publisher: ", err)
 	}
+	oidcProviders := cfg.NewOidcProviders()
+	webAuthn, err := cfg.NewWebAuthn()
+	if err != nil {
+		log.Fatal("failed init webauthn: ", err)
+	}
 
 	signoz, _ := cfg.NewSignoz()
 	defer func() {
@@ -55,7 +66,7 @@ func main() {
 		}
 	}()
 
-	app := app.NewApp(cfg, db, mailer, storage, cache, publisher, nil)
+	app := app.NewApp(cfg, db, mailer, smsSender, storage, cache, publisher, nil, oidcProviders, keySet, webAuthn)
 	handler := handler.NewHandler(app)
 	router := chi.NewRouter()
 
@@ -70,6 +81,7 @@ func main() {
 	})
 
 	router.Get("/healthz", handler.Healthz)
+	router.Get("/.well-known/jwks.json", handler.GetJwks)
 	router.Group(func(r chi.Router) {
 		r.Use(handler.InstrumentMiddleware)
 
@@ -88,12 +100,15 @@ func main() {
 
 		// File
 		r.Route("/files", func(r chi.Router) {
-			r.Post("/upload", handler.UploadFile)
+			r.With(handler.RateLimitMiddleware("upload-file", cfg.UPLOAD_FILE_RATE_LIMIT, time.Duration(cfg.UPLOAD_FILE_RATE_LIMIT_TTL)*time.Second, handler.RateLimitByIP)).
+				Post("/upload", handler.UploadFile)
 		})
 
 		// Auth
 		r.Route("/auth", func(r chi.Router) {
 			r.Route("/register", func(r chi.Router) {
+				r.Use(handler.RateLimitMiddleware("register", cfg.REGISTER_RATE_LIMIT, time.Duration(cfg.REGISTER_RATE_LIMIT_TTL)*time.Second, handler.RateLimitByIP))
+
 				r.Post("/", handler.Register)
 				r.Post("/resend-verification", handler.RegisterResendVerification)
 				r.Post("/verify-account", handler.VerifyAccount)
@@ -101,29 +116,147 @@ func main() {
 			r.Post("/login", handler.Login)
 			r.Post("/refresh-session", handler.RefreshSession)
 			r.Route("/mfa", func(r chi.Router) {
-				r.Use(handler.AuthMfaMiddleware)
-
 				r.Route("/otp", func(r chi.Router) {
+					r.Use(handler.AuthMfaMiddleware)
+
 					r.Post("/send", handler.SendMfaOtp)
 					r.Post("/validate", handler.ValidateMfaOtp)
 				})
+				r.Route("/webauthn", func(r chi.Router) {
+					r.Use(handler.AuthMfaMiddleware)
+
+					r.Post("/begin", handler.BeginWebauthnMfa)
+					r.Post("/finish", handler.FinishWebauthnMfa)
+				})
+				r.Route("/totp", func(r chi.Router) {
+					r.Use(handler.AuthMiddleware)
+					r.Use(handler.RejectApiKeyMiddleware)
+
+					r.Post("/enroll", handler.EnrollMfaTotp)
+					r.Post("/confirm", handler.ConfirmMfaTotp)
+				})
+				r.Route("/recovery-codes", func(r chi.Router) {
+					r.Use(handler.AuthMiddleware)
+					r.Use(handler.RejectApiKeyMiddleware)
+
+					r.Post("/regenerate", handler.RegenerateMfaRecoveryCodes)
+				})
+			})
+			r.With(handler.RateLimitMiddleware("forgot-password", cfg.FORGOT_PASSWORD_RATE_LIMIT, time.Duration(cfg.FORGOT_PASSWORD_RATE_LIMIT_TTL)*time.Second, handler.RateLimitByIP)).
+				Post("/forgot-password", handler.ForgotPassword)
+			r.With(handler.RateLimitMiddleware("reset-password", cfg.VERIFY_CODE_RATE_LIMIT, time.Duration(cfg.VERIFY_CODE_RATE_LIMIT_TTL)*time.Second, handler.RateLimitByIP)).
+				Post("/reset-password", handler.ResetPassword)
+			r.Route("/magic-link", func(r chi.Router) {
+				r.With(handler.RateLimitMiddleware("magic-link", cfg.MAGIC_LINK_RATE_LIMIT, time.Duration(cfg.MAGIC_LINK_RATE_LIMIT_TTL)*time.Second, handler.RateLimitByIP)).
+					Post("/send", handler.SendMagicLink)
+				r.With(handler.RateLimitMiddleware("magic-link-verify", cfg.VERIFY_CODE_RATE_LIMIT, time.Duration(cfg.VERIFY_CODE_RATE_LIMIT_TTL)*time.Second, handler.RateLimitByIP)).
+					Post("/verify", handler.VerifyMagicLink)
 			})
-			r.Post("/forgot-password", handler.ForgotPassword)
-			r.Post("/reset-password", handler.ResetPassword)
 			r.Route("/sso", func(r chi.Router) {
-				r.Post("/google", handler.SsoGoogle)
+				r.Use(handler.RateLimitMiddleware("sso-login", cfg.LOGIN_RATE_LIMIT, time.Duration(cfg.LOGIN_RATE_LIMIT_TTL)*time.Second, handler.RateLimitByIP))
+
+				r.Post("/{provider}", handler.SsoLogin)
+			})
+			r.Route("/webauthn", func(r chi.Router) {
+				r.Group(func(r chi.Router) {
+					r.Use(handler.RateLimitMiddleware("webauthn-login", cfg.LOGIN_RATE_LIMIT, time.Duration(cfg.LOGIN_RATE_LIMIT_TTL)*time.Second, handler.RateLimitByIP))
+
+					r.Post("/login/begin", handler.BeginWebauthnLogin)
+					r.Post("/login/finish", handler.FinishWebauthnLogin)
+				})
+				r.Group(func(r chi.Router) {
+					r.Use(handler.AuthMiddleware)
+					r.Use(handler.RejectApiKeyMiddleware)
+
+					r.Post("/register/begin", handler.BeginWebauthnRegistration)
+					r.Post("/register/finish", handler.FinishWebauthnRegistration)
+					r.Get("/credentials", handler.GetWebauthnCredentials)
+					r.Delete("/credentials/{ID}", handler.DeleteWebauthnCredential)
+				})
+			})
+			r.Group(func(r chi.Router) {
+				r.Use(handler.AuthMiddleware)
+				r.Use(handler.RejectApiKeyMiddleware)
+				r.Use(handler.RateLimitMiddleware("auth", cfg.USER_RATE_LIMIT, time.Duration(cfg.USER_RATE_LIMIT_TTL)*time.Second, handler.RateLimitByUser))
+
+				r.Get("/sessions", handler.GetSessions)
+				r.Delete("/sessions/{ID}", handler.RevokeSession)
+				r.Post("/logout", handler.Logout)
+				r.Post("/logout-all", handler.LogoutAll)
+				r.Get("/api-keys", handler.GetApiKeys)
+				r.Post("/api-keys", handler.CreateApiKey)
+				r.Delete("/api-keys/{ID}", handler.RevokeApiKey)
+			})
+		})
+
+		// Me
+		r.Route("/me", func(r chi.Router) {
+			r.With(handler.RateLimitMiddleware("change-email-confirm", cfg.VERIFY_CODE_RATE_LIMIT, time.Duration(cfg.VERIFY_CODE_RATE_LIMIT_TTL)*time.Second, handler.RateLimitByIP)).
+				Post("/email/confirm", handler.ConfirmChangeEmail)
+			r.Group(func(r chi.Router) {
+				r.Use(handler.AuthMiddleware)
+				r.Use(handler.RejectApiKeyMiddleware)
+				r.Use(handler.RateLimitMiddleware("me", cfg.USER_RATE_LIMIT, time.Duration(cfg.USER_RATE_LIMIT_TTL)*time.Second, handler.RateLimitByUser))
+
+				r.Get("/", handler.GetProfile)
+				r.Put("/", handler.UpdateProfile)
+				r.Delete("/", handler.DeleteAccount)
+				r.With(handler.RateLimitMiddleware("upload-avatar", cfg.UPLOAD_FILE_RATE_LIMIT, time.Duration(cfg.UPLOAD_FILE_RATE_LIMIT_TTL)*time.Second, handler.RateLimitByIP)).
+					Post("/avatar", handler.UploadAvatar)
+				r.Put("/password", handler.ChangePassword)
+				r.Put("/email", handler.ChangeEmail)
 			})
 		})
 
 		// User
 		r.Route("/users", func(r chi.Router) {
 			r.Use(handler.AuthMiddleware)
+			r.Use(handler.RateLimitMiddleware("users", cfg.USER_RATE_LIMIT, time.Duration(cfg.USER_RATE_LIMIT_TTL)*time.Second, handler.RateLimitByAPIKey))
+
+			r.With(handler.RequirePermission(constant.PermissionUsersRead)).Get("/", handler.GetUsers)
+			r.With(handler.RequirePermission(constant.PermissionUsersCreate)).Post("/", handler.CreateUser)
+			r.With(handler.RequirePermission(constant.PermissionUsersCreate)).Post("/import", handler.ImportUsers)
+			r.With(handler.RequirePermission(constant.PermissionUsersRead)).Post("/export", handler.ExportUsers)
+			r.With(handler.RequirePermission(constant.PermissionUsersRead)).Get("/transfers/{ID}", handler.GetUserTransfer)
+			r.With(handler.RequirePermission(constant.PermissionUsersRead)).Get("/{ID}", handler.GetUser)
+			r.With(handler.RequirePermission(constant.PermissionUsersUpdate)).Put("/{ID}", handler.UpdateUser)
+			r.With(handler.RequirePermission(constant.PermissionUsersDelete)).Delete("/{ID}", handler.DeleteUser)
+			r.With(handler.RequirePermission(constant.PermissionUsersUpdate)).Post("/{ID}/deactivate", handler.DeactivateUser)
+			r.With(handler.RequirePermission(constant.PermissionUsersUpdate)).Post("/{ID}/reactivate", handler.ReactivateUser)
+		})
+
+		// Job
+		r.Route("/jobs", func(r chi.Router) {
+			r.Use(handler.AuthMiddleware)
+			r.Use(handler.RateLimitMiddleware("jobs", cfg.USER_RATE_LIMIT, time.Duration(cfg.USER_RATE_LIMIT_TTL)*time.Second, handler.RateLimitByUser))
+
+			r.Get("/{ID}", handler.GetJob)
+		})
+
+		// OAuth
+		r.Route("/oauth", func(r chi.Router) {
+			r.With(handler.RateLimitMiddleware("oauth-token", cfg.OAUTH_TOKEN_RATE_LIMIT, time.Duration(cfg.OAUTH_TOKEN_RATE_LIMIT_TTL)*time.Second, handler.RateLimitByIP)).
+				Post("/token", handler.OauthToken)
+		})
+
+		// API Client
+		r.Route("/api-clients", func(r chi.Router) {
+			r.Use(handler.AuthMiddleware)
+			r.Use(handler.RateLimitMiddleware("api-clients", cfg.USER_RATE_LIMIT, time.Duration(cfg.USER_RATE_LIMIT_TTL)*time.Second, handler.RateLimitByAPIKey))
+			r.Use(handler.RequirePermission(constant.PermissionApiClientsManage))
+
+			r.Get("/", handler.GetApiClients)
+			r.Post("/", handler.CreateApiClient)
+			r.Delete("/{ID}", handler.DeleteApiClient)
+		})
+
+		// Audit Log
+		r.Route("/audit-logs", func(r chi.Router) {
+			r.Use(handler.AuthMiddleware)
+			r.Use(handler.RateLimitMiddleware("audit-logs", cfg.USER_RATE_LIMIT, time.Duration(cfg.USER_RATE_LIMIT_TTL)*time.Second, handler.RateLimitByAPIKey))
+			r.Use(handler.RequirePermission(constant.PermissionAuditLogsRead))
 
-			r.Get("/", handler.GetUsers)
-			r.Post("/", handler.CreateUser)
-			r.Get("/{ID}", handler.GetUser)
-			r.Put("/{ID}", handler.UpdateUser)
-			r.Delete("/{ID}", handler.DeleteUser)
+			r.Get("/", handler.GetAuditLogs)
 		})
 	})
 
@@ -92,5 +92,30 @@ func main() {
 		for _, record := range m {
 			fmt.Printf("%s - %s\n", record.Id, record.AppliedAt)
 		}
+	case "assign-admin":
+		if len(os.Args) < 3 {
+			log.Println("Email is required for assign-admin action!")
+			return
+		}
+		email := strings.ToLower(strings.TrimSpace(os.Args[2]))
+
+		// The roles are seeded by the migrations, so run it after "up"
+		res, err := db.Exec(`
+			INSERT INTO user_roles (user_id, role_id)
+			SELECT users.id, roles.id FROM users, roles
+			WHERE LOWER(users.email) = $1 AND users.deleted_at IS NULL AND roles.slug = 'admin'
+			ON CONFLICT DO NOTHING`, email)
+		if err != nil {
+			panic(err)
+		}
+		assigned, err := res.RowsAffected()
+		if err != nil {
+			panic(err)
+		}
+		if assigned == 0 {
+			fmt.Printf("No admin role assigned, %s is not found or already an admin\n", email)
+			return
+		}
+		fmt.Printf("Assigned admin role to %s!\n", email)
 	}
 }
@@ -28,7 +28,12 @@ func main() {
 	if err != nil {
 		log.Fatal("failed connect to database: ", err)
 	}
+	keySet, err := cfg.NewIDTokenKeySet()
+	if err != nil {
+		log.Fatal("failed load id token keys: ", err)
+	}
 	mailer := cfg.NewSMTP()
+	smsSender := cfg.NewSmsSender()
 	storage := cfg.NewStorage()
 	cache, err := cfg.NewCache()
 	if err != nil {
@@ -39,7 +44,7 @@ func main() {
 		log.Fatal("failed connect to publisher: ", err)
 	}
 
-	app := app.NewApp(cfg, db, mailer, storage, cache, publisher, nil)
+	app := app.NewApp(cfg, db, mailer, smsSender, storage, cache, publisher, nil, nil, keySet, nil)
 
 	// create a scheduler
 	s, err := scheduler.NewScheduler(app)
@@ -49,6 +54,8 @@ func main() {
 
 	// add a job to the scheduler
 	s.RegisterJob(gocron.DurationJob(60*time.Second), "CronTest", s.App.Usecase.CronTest)
+	s.RegisterJob(gocron.DurationJob(time.Hour), "CleanupUserVerifications", s.App.Usecase.CleanupUserVerifications)
+	s.RegisterJob(gocron.DurationJob(time.Hour), "CleanupJobs", s.App.Usecase.CleanupJobs)
 
 	// start the scheduler
 	s.Start()
@@ -32,7 +32,12 @@ func main() {
 	if err != nil {
 		log.Fatal("failed connect to database: ", err)
 	}
+	keySet, err := cfg.NewIDTokenKeySet()
+	if err != nil {
+		log.Fatal("failed load id token keys: ", err)
+	}
 	mailer := cfg.NewSMTP()
+	smsSender := cfg.NewSmsSender()
 	storage := cfg.NewStorage()
 	cache, err := cfg.NewCache()
 	if err != nil {
@@ -43,7 +48,7 @@ func main() {
 		log.Fatal("failed connect to publisher: ", err)
 	}
 
-	app := app.NewApp(cfg, db, mailer, storage, cache, publisher, nil)
+	app := app.NewApp(cfg, db, mailer, smsSender, storage, cache, publisher, nil, nil, keySet, nil)
 	handler := handler.NewHandler(app)
 
 	// Create and start websocket hub
@@ -25,7 +25,12 @@ func main() {
 	if err != nil {
 		log.Fatal("failed connect to database: ", err)
 	}
+	keySet, err := cfg.NewIDTokenKeySet()
+	if err != nil {
+		log.Fatal("failed load id token keys: ", err)
+	}
 	mailer := cfg.NewSMTP()
+	smsSender := cfg.NewSmsSender()
 	storage := cfg.NewStorage()
 	cache, err := cfg.NewCache()
 	if err != nil {
@@ -37,13 +42,18 @@ func main() {
 	}
 	wsPool := cfg.NewWebsocketPool(20)
 
-	app := app.NewApp(cfg, db, mailer, storage, cache, publisher, wsPool)
+	app := app.NewApp(cfg, db, mailer, smsSender, storage, cache, publisher, wsPool, nil, keySet, nil)
 	worker := worker.NewWorker(app)
 	server := cfg.NewConsumer()
 
 	mux := asynq.NewServeMux()
 	worker.RegisterWorker(mux, constant.TaskTypeEmailSend, "WorkerSendEmail", false, worker.WorkerSendEmail)
+	worker.RegisterWorker(mux, constant.TaskTypeSmsSend, "WorkerSendSms", false, worker.WorkerSendSms)
 	worker.RegisterWorker(mux, constant.TaskTypeWebsocketBroadcastMessage, "WorkerBroadcastWebsocketMessage", false, worker.WorkerBroadcastWebsocketMessage)
+	worker.RegisterWorker(mux, constant.TaskTypeUserErase, "WorkerEraseUser", false, worker.WorkerEraseUser)
+	// A failed import or export is reported in its status instead of being retried, a retry could create users twice
+	worker.RegisterWorker(mux, constant.TaskTypeUserImport, "WorkerImportUsers", true, worker.WorkerImportUsers)
+	worker.RegisterWorker(mux, constant.TaskTypeUserExport, "WorkerExportUsers", true, worker.WorkerExportUsers)
 
 	if err := server.Run(mux); err != nil {
 		log.Fatalf("consumer server failed to start: %v", err)
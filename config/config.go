@@ -1,9 +1,15 @@
 package config
 
 import (
+	"app/lib/oidc"
+	"fmt"
 	"log"
+	"net/netip"
 	"os"
 	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
 )
 
 type Config struct {
@@ -13,10 +19,11 @@ type Config struct {
 
 	// Server Configuration
 	SERVER_PORT             string
-	SERVER_WRITE_TIMEOUT    int // In seconds
-	SERVER_READ_TIMEOUT     int // In seconds
-	SERVER_IDLE_TIMEOUT     int // In seconds
-	SERVER_SHUTDOWN_TIMEOUT int // In seconds
+	SERVER_WRITE_TIMEOUT    int            // In seconds
+	SERVER_READ_TIMEOUT     int            // In seconds
+	SERVER_IDLE_TIMEOUT     int            // In seconds
+	SERVER_SHUTDOWN_TIMEOUT int            // In seconds
+	TRUSTED_PROXIES         []netip.Prefix // IPs or CIDRs separated by comma, the proxy headers are only read from them
 
 	// Websocket Configuration
 	SERVER_WEBSOCKET_PORT             string
@@ -41,6 +48,13 @@ type Config struct {
 	SMTP_SENDER      string
 	SMTP_SENDER_NAME string
 
+	// SMS Configuration
+	SMS_VENDOR       string
+	SMS_HTTP_URL     string
+	SMS_HTTP_API_KEY string
+	SMS_HTTP_TIMEOUT int // In seconds
+	SMS_SENDER_ID    string
+
 	// Storage Configuration
 	STORAGE_VENDOR             string
 	STORAGE_BUCKET_NAME        string
@@ -67,15 +81,66 @@ type Config struct {
 	// Auth Configuration
 	SEND_VERIFICATION_DELAY_TTL int // In seconds
 	MFA_FLAG_TTL                int // In seconds
+	VERIFY_ACCOUNT_TTL          int // In seconds
+	RESET_PASSWORD_TTL          int // In seconds
+	MAGIC_LINK_TTL              int // In seconds
+	CHANGE_EMAIL_TTL            int // In seconds
+	VERIFICATION_RETENTION      int // In seconds, expired or used verifications older than this are swept
 	ID_TOKEN_HMAC_KEY           string
+	ID_TOKEN_HMAC_DISABLED      bool     // Stop accepting hmac id tokens once every token issued before the asymmetric keys expired
+	ID_TOKEN_SIGNING_KEYS       []string // [kid]=[pem_path] list, separated by comma
+	ID_TOKEN_SIGNING_KID        string
 	ACCESS_TOKEN_TTL            int // In seconds
 	REFRESH_TOKEN_TTL           int // In seconds
 	MFA_ACCESS_TOKEN_TTL        int // In seconds
 	ID_TOKEN_TTL                int // In seconds
+	OAUTH_ACCESS_TOKEN_TTL      int // In seconds
 	TOTP_PERIOD                 int // In seconds
 	SEND_OTP_MAX_RATE_LIMIT     int
 	SEND_OTP_MAX_RATE_LIMIT_TTL int // In seconds
 	SEND_OTP_DELAY_TTL          int // In seconds
+	LOGIN_MAX_FAILED_ATTEMPT    int
+	LOGIN_IP_MAX_FAILED_ATTEMPT int
+	LOGIN_FAILED_ATTEMPT_TTL    int // In seconds
+	LOGIN_LOCKOUT_TTL           int // In seconds, doubled on every consecutive lockout
+	LOGIN_LOCKOUT_MAX_TTL       int // In seconds
+	MFA_MAX_FAILED_ATTEMPT      int // Failed mfa otp attempts before the user is locked, the lockout uses the login lockout ttl
+	BCRYPT_COST                 int
+	BREACHED_PASSWORD_DIR       string // Directory of k-anonymity range files named by sha1 prefix, empty to disable
+
+	// Rate Limit Configuration
+	REGISTER_RATE_LIMIT            int
+	REGISTER_RATE_LIMIT_TTL        int // In seconds
+	FORGOT_PASSWORD_RATE_LIMIT     int
+	FORGOT_PASSWORD_RATE_LIMIT_TTL int // In seconds
+	MAGIC_LINK_RATE_LIMIT          int
+	MAGIC_LINK_RATE_LIMIT_TTL      int // In seconds
+	VERIFY_CODE_RATE_LIMIT         int
+	VERIFY_CODE_RATE_LIMIT_TTL     int // In seconds
+	UPLOAD_FILE_RATE_LIMIT         int
+	UPLOAD_FILE_RATE_LIMIT_TTL     int // In seconds
+	USER_RATE_LIMIT                int // Per user or api key on the authenticated routes
+	USER_RATE_LIMIT_TTL            int // In seconds
+	OAUTH_TOKEN_RATE_LIMIT         int
+	OAUTH_TOKEN_RATE_LIMIT_TTL     int // In seconds
+	LOGIN_RATE_LIMIT               int // Per ip on the sso and passkey login routes
+	LOGIN_RATE_LIMIT_TTL           int // In seconds
+
+	// User Transfer Configuration
+	USER_TRANSFER_BATCH_SIZE int // Users created or exported per batch
+
+	// Job Configuration
+	JOB_RETENTION int // In seconds, finished jobs older than this are swept
+
+	// SSO Configuration
+	SSO_PROVIDERS      map[string]SsoProviderConfig // Parsed from SSO_PROVIDERS, SSO_[PROVIDER]_ISSUER & SSO_[PROVIDER]_CLIENT_IDS
+	SSO_JWKS_CACHE_TTL int                          // In seconds
+
+	// WebAuthn Configuration
+	WEBAUTHN_RP_ID       string
+	WEBAUTHN_RP_NAME     string
+	WEBAUTHN_RP_ORIGINS  []string // Separated by comma
+	WEBAUTHN_SESSION_TTL int      // In seconds
 
 	// Signoz Configuration
 	SIGNOZ_URL               string
@@ -84,6 +149,17 @@ type Config struct {
 	SIGNOZ_TRACE_SAMPLE_RATE float64
 }
 
+type SsoProviderConfig struct {
+	Issuer    string
+	ClientIDs []string
+}
+
+// defaultSsoIssuers is used when SSO_[PROVIDER]_ISSUER is not set
+var defaultSsoIssuers = map[string]string{
+	oidc.ProviderGoogle:    "https://accounts.google.com",
+	oidc.ProviderMicrosoft: "https://login.microsoftonline.com/common/v2.0",
+}
+
 func InitConfig() *Config {
 	return &Config{
 		ENV:                               os.Getenv("ENV"),
@@ -93,6 +169,7 @@ func InitConfig() *Config {
 		SERVER_READ_TIMEOUT:               parseIntConfig("SERVER_READ_TIMEOUT", 30),
 		SERVER_IDLE_TIMEOUT:               parseIntConfig("SERVER_IDLE_TIMEOUT", 30),
 		SERVER_SHUTDOWN_TIMEOUT:           parseIntConfig("SERVER_SHUTDOWN_TIMEOUT", 30),
+		TRUSTED_PROXIES:                   parsePrefixListConfig("TRUSTED_PROXIES"),
 		SERVER_WEBSOCKET_PORT:             os.Getenv("SERVER_WEBSOCKET_PORT"),
 		WEBSOCKET_SERVER_SHUTDOWN_TIMEOUT: parseIntConfig("WEBSOCKET_SERVER_SHUTDOWN_TIMEOUT", 30),
 		WEBSOCKET_URL:                     os.Getenv("WEBSOCKET_URL"),
@@ -103,6 +180,11 @@ func InitConfig() *Config {
 		SMTP_USERNAME:                     os.Getenv("SMTP_USERNAME"),
 		SMTP_SENDER:                       os.Getenv("SMTP_SENDER"),
 		SMTP_SENDER_NAME:                  os.Getenv("SMTP_SENDER_NAME"),
+		SMS_VENDOR:                        os.Getenv("SMS_VENDOR"),
+		SMS_HTTP_URL:                      os.Getenv("SMS_HTTP_URL"),
+		SMS_HTTP_API_KEY:                  os.Getenv("SMS_HTTP_API_KEY"),
+		SMS_HTTP_TIMEOUT:                  parseIntConfig("SMS_HTTP_TIMEOUT", 10),
+		SMS_SENDER_ID:                     os.Getenv("SMS_SENDER_ID"),
 		DB_USER:                           os.Getenv("DB_USER"),
 		DB_PASSWORD:                       os.Getenv("DB_PASSWORD"),
 		DB_HOST:                           os.Getenv("DB_HOST"),
@@ -127,15 +209,56 @@ func InitConfig() *Config {
 		LOG_PATH:                          os.Getenv("LOG_PATH"),
 		SEND_VERIFICATION_DELAY_TTL:       parseIntConfig("SEND_VERIFICATION_DELAY_TTL", 60),
 		MFA_FLAG_TTL:                      parseIntConfig("MFA_FLAG_TTL", 604800),
+		VERIFY_ACCOUNT_TTL:                parseIntConfig("VERIFY_ACCOUNT_TTL", 86400),
+		RESET_PASSWORD_TTL:                parseIntConfig("RESET_PASSWORD_TTL", 3600),
+		MAGIC_LINK_TTL:                    parseIntConfig("MAGIC_LINK_TTL", 900),
+		CHANGE_EMAIL_TTL:                  parseIntConfig("CHANGE_EMAIL_TTL", 3600),
+		VERIFICATION_RETENTION:            parseIntConfig("VERIFICATION_RETENTION", 604800),
 		ID_TOKEN_HMAC_KEY:                 os.Getenv("ID_TOKEN_HMAC_KEY"),
+		ID_TOKEN_HMAC_DISABLED:            parseBoolConfig("ID_TOKEN_HMAC_DISABLED"),
+		ID_TOKEN_SIGNING_KEYS:             parseListConfig("ID_TOKEN_SIGNING_KEYS"),
+		ID_TOKEN_SIGNING_KID:              os.Getenv("ID_TOKEN_SIGNING_KID"),
 		ACCESS_TOKEN_TTL:                  parseIntConfig("ACCESS_TOKEN_TTL", 86400),
 		REFRESH_TOKEN_TTL:                 parseIntConfig("REFRESH_TOKEN_TTL", 604800),
 		MFA_ACCESS_TOKEN_TTL:              parseIntConfig("MFA_ACCESS_TOKEN_TTL", 3600),
 		ID_TOKEN_TTL:                      parseIntConfig("ID_TOKEN_TTL", 86400),
+		OAUTH_ACCESS_TOKEN_TTL:            parseIntConfig("OAUTH_ACCESS_TOKEN_TTL", 3600),
 		TOTP_PERIOD:                       parseIntConfig("TOTP_PERIOD", 120),
 		SEND_OTP_MAX_RATE_LIMIT:           parseIntConfig("SEND_OTP_MAX_RATE_LIMIT", 3),
 		SEND_OTP_MAX_RATE_LIMIT_TTL:       parseIntConfig("SEND_OTP_MAX_RATE_LIMIT_TTL", 3600),
 		SEND_OTP_DELAY_TTL:                parseIntConfig("SEND_OTP_DELAY_TTL", 120),
+		LOGIN_MAX_FAILED_ATTEMPT:          parseIntConfig("LOGIN_MAX_FAILED_ATTEMPT", 5),
+		LOGIN_IP_MAX_FAILED_ATTEMPT:       parseIntConfig("LOGIN_IP_MAX_FAILED_ATTEMPT", 20),
+		LOGIN_FAILED_ATTEMPT_TTL:          parseIntConfig("LOGIN_FAILED_ATTEMPT_TTL", 900),
+		MFA_MAX_FAILED_ATTEMPT:            parseIntConfig("MFA_MAX_FAILED_ATTEMPT", 5),
+		LOGIN_LOCKOUT_TTL:                 parseIntConfig("LOGIN_LOCKOUT_TTL", 60),
+		LOGIN_LOCKOUT_MAX_TTL:             parseIntConfig("LOGIN_LOCKOUT_MAX_TTL", 86400),
+		BCRYPT_COST:                       parseBcryptCostConfig("BCRYPT_COST", 10),
+		BREACHED_PASSWORD_DIR:             os.Getenv("BREACHED_PASSWORD_DIR"),
+		REGISTER_RATE_LIMIT:               parseIntConfig("REGISTER_RATE_LIMIT", 10),
+		REGISTER_RATE_LIMIT_TTL:           parseIntConfig("REGISTER_RATE_LIMIT_TTL", 3600),
+		FORGOT_PASSWORD_RATE_LIMIT:        parseIntConfig("FORGOT_PASSWORD_RATE_LIMIT", 5),
+		FORGOT_PASSWORD_RATE_LIMIT_TTL:    parseIntConfig("FORGOT_PASSWORD_RATE_LIMIT_TTL", 3600),
+		MAGIC_LINK_RATE_LIMIT:             parseIntConfig("MAGIC_LINK_RATE_LIMIT", 5),
+		MAGIC_LINK_RATE_LIMIT_TTL:         parseIntConfig("MAGIC_LINK_RATE_LIMIT_TTL", 3600),
+		VERIFY_CODE_RATE_LIMIT:            parseIntConfig("VERIFY_CODE_RATE_LIMIT", 10),
+		VERIFY_CODE_RATE_LIMIT_TTL:        parseIntConfig("VERIFY_CODE_RATE_LIMIT_TTL", 900),
+		UPLOAD_FILE_RATE_LIMIT:            parseIntConfig("UPLOAD_FILE_RATE_LIMIT", 30),
+		UPLOAD_FILE_RATE_LIMIT_TTL:        parseIntConfig("UPLOAD_FILE_RATE_LIMIT_TTL", 60),
+		USER_RATE_LIMIT:                   parseIntConfig("USER_RATE_LIMIT", 300),
+		USER_RATE_LIMIT_TTL:               parseIntConfig("USER_RATE_LIMIT_TTL", 60),
+		OAUTH_TOKEN_RATE_LIMIT:            parseIntConfig("OAUTH_TOKEN_RATE_LIMIT", 30),
+		OAUTH_TOKEN_RATE_LIMIT_TTL:        parseIntConfig("OAUTH_TOKEN_RATE_LIMIT_TTL", 60),
+		LOGIN_RATE_LIMIT:                  parseIntConfig("LOGIN_RATE_LIMIT", 30),
+		LOGIN_RATE_LIMIT_TTL:              parseIntConfig("LOGIN_RATE_LIMIT_TTL", 60),
+		USER_TRANSFER_BATCH_SIZE:          parseIntConfig("USER_TRANSFER_BATCH_SIZE", 100),
+		JOB_RETENTION:                     parseIntConfig("JOB_RETENTION", 604800),
+		SSO_PROVIDERS:                     parseSsoProvidersConfig("SSO_PROVIDERS"),
+		SSO_JWKS_CACHE_TTL:                parseIntConfig("SSO_JWKS_CACHE_TTL", 3600),
+		WEBAUTHN_RP_ID:                    os.Getenv("WEBAUTHN_RP_ID"),
+		WEBAUTHN_RP_NAME:                  os.Getenv("WEBAUTHN_RP_NAME"),
+		WEBAUTHN_RP_ORIGINS:               parseListConfig("WEBAUTHN_RP_ORIGINS"),
+		WEBAUTHN_SESSION_TTL:              parseIntConfig("WEBAUTHN_SESSION_TTL", 300),
 		SIGNOZ_URL:                        os.Getenv("SIGNOZ_URL"),
 		SIGNOZ_SERVICE_NAME:               os.Getenv("SIGNOZ_SERVICE_NAME"),
 		SIGNOZ_SERVICE_NAMESPACE:          os.Getenv("SIGNOZ_SERVICE_NAMESPACE"),
@@ -143,6 +266,15 @@ func InitConfig() *Config {
 	}
 }
 
+// parseBcryptCostConfig reject a cost outside of the bcrypt range at startup instead of failing every password hash
+func parseBcryptCostConfig(envName string, defaultValue int) int {
+	cost := parseIntConfig(envName, defaultValue)
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		log.Fatalf("failed parsing config: %s must be between %d and %d", envName, bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	return cost
+}
+
 func parseIntConfig(envName string, defaultValue int) int {
 	envValue := os.Getenv(envName)
 	if envValue != "" {
@@ -178,3 +310,58 @@ func parseBoolConfig(envName string) bool {
 	}
 	return false
 }
+
+func parseListConfig(envName string) []string {
+	result := []string{}
+	for _, value := range strings.Split(os.Getenv(envName), ",") {
+		value = strings.TrimSpace(value)
+		if value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// parsePrefixListConfig parse a list of IPs or CIDRs, a single IP is parsed as a prefix matching only itself
+func parsePrefixListConfig(envName string) []netip.Prefix {
+	result := []netip.Prefix{}
+	for _, value := range parseListConfig(envName) {
+		if !strings.Contains(value, "/") {
+			addr, err := netip.ParseAddr(value)
+			if err != nil {
+				log.Fatalf("failed parsing config: %s", envName)
+			}
+			result = append(result, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			log.Fatalf("failed parsing config: %s", envName)
+		}
+		result = append(result, prefix.Masked())
+	}
+	return result
+}
+
+func parseSsoProvidersConfig(envName string) map[string]SsoProviderConfig {
+	result := map[string]SsoProviderConfig{}
+	for _, provider := range parseListConfig(envName) {
+		provider = strings.ToLower(provider)
+		envPrefix := fmt.Sprintf("SSO_%s", strings.ToUpper(provider))
+
+		issuer := os.Getenv(envPrefix + "_ISSUER")
+		if issuer == "" {
+			issuer = defaultSsoIssuers[provider]
+		}
+		if issuer == "" {
+			log.Fatalf("failed parsing config: %s_ISSUER", envPrefix)
+		}
+
+		result[provider] = SsoProviderConfig{
+			Issuer:    issuer,
+			ClientIDs: parseListConfig(envPrefix + "_CLIENT_IDS"),
+		}
+	}
+	return result
+}
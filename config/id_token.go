@@ -0,0 +1,45 @@
+package config
+
+import (
+	"app/lib/auth"
+	"fmt"
+	"log"
+	"strings"
+)
+
+func (c *Config) NewIDTokenKeySet() (*auth.KeySet, error) {
+	keySet := &auth.KeySet{
+		SigningKid: c.ID_TOKEN_SIGNING_KID,
+		Keys:       map[string]*auth.SigningKey{},
+		HmacKey:    []byte(c.ID_TOKEN_HMAC_KEY),
+	}
+	if c.ID_TOKEN_HMAC_DISABLED {
+		keySet.HmacKey = nil
+	}
+
+	for _, signingKeyConfig := range c.ID_TOKEN_SIGNING_KEYS {
+		kid, path, ok := strings.Cut(signingKeyConfig, "=")
+		if !ok || kid == "" || path == "" {
+			return nil, fmt.Errorf("invalid ID_TOKEN_SIGNING_KEYS entry %q", signingKeyConfig)
+		}
+
+		signingKey, err := auth.LoadSigningKey(kid, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed load id token signing key %s: %w", kid, err)
+		}
+		keySet.Keys[kid] = signingKey
+	}
+
+	if len(keySet.Keys) == 0 {
+		if c.ID_TOKEN_HMAC_DISABLED {
+			return nil, fmt.Errorf("ID_TOKEN_HMAC_DISABLED requires ID_TOKEN_SIGNING_KEYS")
+		}
+		log.Println("using hmac id token signing key")
+		return keySet, nil
+	}
+	if _, ok := keySet.Keys[keySet.SigningKid]; !ok {
+		return nil, fmt.Errorf("ID_TOKEN_SIGNING_KID %q is not one of ID_TOKEN_SIGNING_KEYS", keySet.SigningKid)
+	}
+
+	return keySet, nil
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"app/lib/oidc"
+	"log"
+	"net/http"
+	"time"
+)
+
+func (c *Config) NewOidcProviders() oidc.Providers {
+	providers := oidc.Providers{}
+	for name, providerConfig := range c.SSO_PROVIDERS {
+		if len(providerConfig.ClientIDs) == 0 {
+			log.Printf("skip sso provider %s: no client id configured", name)
+			continue
+		}
+
+		providers[name] = &oidc.Provider{
+			Name:         name,
+			Issuer:       providerConfig.Issuer,
+			Audiences:    providerConfig.ClientIDs,
+			Client:       &http.Client{Timeout: 10 * time.Second},
+			JwksCacheTtl: time.Duration(c.SSO_JWKS_CACHE_TTL) * time.Second,
+		}
+	}
+	return providers
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"app/lib/sms"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// localSmsEnvs are the environments allowed to write the sms into a local file instead of sending them
+var localSmsEnvs = []string{"", "local", "dev", "development"}
+
+func (c *Config) NewSmsSender() sms.Sender {
+	switch c.SMS_VENDOR {
+	case "http":
+		return &sms.HTTP{
+			Client:   &http.Client{Timeout: time.Duration(c.SMS_HTTP_TIMEOUT) * time.Second},
+			URL:      c.SMS_HTTP_URL,
+			APIKey:   c.SMS_HTTP_API_KEY,
+			SenderID: c.SMS_SENDER_ID,
+		}
+	case "", "local":
+		// A misconfigured production must not silently drop the otp codes into a file
+		if !slices.Contains(localSmsEnvs, c.ENV) {
+			log.Fatalf("failed parsing config: SMS_VENDOR must be set in %s environment", c.ENV)
+		}
+		log.Println("using local sms sender")
+		return &sms.Local{Directory: "log"}
+	default:
+		log.Fatalf("failed parsing config: unknown SMS_VENDOR %s", c.SMS_VENDOR)
+		return nil
+	}
+}
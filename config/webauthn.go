@@ -0,0 +1,30 @@
+package config
+
+import (
+	"log"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+func (c *Config) NewWebAuthn() (*webauthn.WebAuthn, error) {
+	if c.WEBAUTHN_RP_ID == "" {
+		log.Println("skip webauthn: no relying party id configured")
+		return nil, nil
+	}
+
+	timeout := webauthn.TimeoutConfig{
+		Enforce:    true,
+		Timeout:    time.Duration(c.WEBAUTHN_SESSION_TTL) * time.Second,
+		TimeoutUVD: time.Duration(c.WEBAUTHN_SESSION_TTL) * time.Second,
+	}
+	return webauthn.New(&webauthn.Config{
+		RPID:          c.WEBAUTHN_RP_ID,
+		RPDisplayName: c.WEBAUTHN_RP_NAME,
+		RPOrigins:     c.WEBAUTHN_RP_ORIGINS,
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        timeout,
+			Registration: timeout,
+		},
+	})
+}
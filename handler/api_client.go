@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"app/lib"
+	"app/lib/auth"
+	"app/lib/signoz"
+	"app/request"
+	"encoding/json"
+	"net/http"
+	"slices"
+)
+
+func (handler *Handler) GetApiClients(w http.ResponseWriter, r *http.Request) {
+	ctx, span := signoz.StartSpan(r.Context(), "handler.GetApiClients")
+	defer span.Finish()
+
+	req := request.GetApiClients{}
+	extractor := URLQueryExtractor{Request: r}
+	mapDataFunc := map[string]func(string) (any, error){
+		"limit":  extractor.ExtractNumber,
+		"page":   extractor.ExtractNumber,
+		"search": extractor.ExtractString,
+		"sort":   extractor.ExtractSliceStringWithComma,
+	}
+
+	err := extractor.ExtractData(mapDataFunc, &req)
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	res, err := handler.App.Usecase.GetApiClients(ctx, req)
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	meta := ResponseMeta{HTTPStatus: http.StatusOK}
+	meta.SerializeFromResponse(res.BasePaginateResponse)
+	WriteSuccess(ctx, w, res.Data, "success", meta)
+}
+
+func (handler *Handler) CreateApiClient(w http.ResponseWriter, r *http.Request) {
+	ctx, span := signoz.StartSpan(r.Context(), "handler.CreateApiClient")
+	defer span.Finish()
+
+	idTokenClaims := auth.GetAuthFromCtx(ctx)
+	if idTokenClaims == nil {
+		WriteError(ctx, w, lib.ErrorUnauthorized)
+		return
+	}
+
+	req := request.CreateApiClient{}
+	err := decodeAndValidateRequest(r, &req)
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+	req.GrantedScopes = append(slices.Clone(idTokenClaims.Permissions), idTokenClaims.Scopes...)
+
+	res, err := handler.App.Usecase.CreateApiClient(ctx, req)
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	WriteSuccess(ctx, w, res, "success", ResponseMeta{HTTPStatus: http.StatusOK})
+}
+
+func (handler *Handler) DeleteApiClient(w http.ResponseWriter, r *http.Request) {
+	ctx, span := signoz.StartSpan(r.Context(), "handler.DeleteApiClient")
+	defer span.Finish()
+
+	req := request.DeleteApiClient{}
+	id, err := getParamUint(r, "ID")
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+	req.ID = id
+
+	err = handler.App.Usecase.DeleteApiClient(ctx, req)
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	WriteSuccess(ctx, w, nil, "success", ResponseMeta{HTTPStatus: http.StatusOK})
+}
+
+// OauthToken accept the client credentials either through basic auth or the form body as described in
+// https://datatracker.ietf.org/doc/html/rfc6749#section-4.4
+func (handler *Handler) OauthToken(w http.ResponseWriter, r *http.Request) {
+	ctx, span := signoz.StartSpan(r.Context(), "handler.OauthToken")
+	defer span.Finish()
+
+	err := r.ParseForm()
+	if err != nil {
+		parseRequestError := lib.ErrorParseRequest
+		parseRequestError.Message = err.Error()
+		WriteError(ctx, w, parseRequestError)
+		return
+	}
+
+	req := request.OauthToken{
+		GrantType:    r.PostForm.Get("grant_type"),
+		ClientID:     r.PostForm.Get("client_id"),
+		ClientSecret: r.PostForm.Get("client_secret"),
+		Scope:        r.PostForm.Get("scope"),
+	}
+	if clientID, clientSecret, ok := r.BasicAuth(); ok {
+		req.ClientID = clientID
+		req.ClientSecret = clientSecret
+	}
+
+	err = req.Validate()
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	res, err := handler.App.Usecase.IssueOauthToken(ctx, req)
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	// Written as a plain token response, oauth2 client libraries do not understand the success body envelope
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(res)
+}
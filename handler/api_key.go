@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"app/lib"
+	"app/lib/auth"
+	"app/lib/signoz"
+	"app/request"
+	"net/http"
+)
+
+func (handler *Handler) GetApiKeys(w http.ResponseWriter, r *http.Request) {
+	ctx, span := signoz.StartSpan(r.Context(), "handler.GetApiKeys")
+	defer span.Finish()
+
+	idTokenClaims := auth.GetAuthFromCtx(ctx)
+	if idTokenClaims.UserID == 0 {
+		WriteError(ctx, w, lib.ErrorUnauthorized)
+		return
+	}
+
+	res, err := handler.App.Usecase.GetApiKeys(ctx, request.GetUserApiKeys{
+		UserID: idTokenClaims.UserID,
+	})
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	WriteSuccess(ctx, w, res, "success", ResponseMeta{HTTPStatus: http.StatusOK})
+}
+
+func (handler *Handler) CreateApiKey(w http.ResponseWriter, r *http.Request) {
+	ctx, span := signoz.StartSpan(r.Context(), "handler.CreateApiKey")
+	defer span.Finish()
+
+	idTokenClaims := auth.GetAuthFromCtx(ctx)
+	if idTokenClaims.UserID == 0 {
+		WriteError(ctx, w, lib.ErrorUnauthorized)
+		return
+	}
+
+	req := request.CreateApiKey{}
+	err := decodeAndValidateRequest(r, &req)
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+	req.UserID = idTokenClaims.UserID
+
+	res, err := handler.App.Usecase.CreateApiKey(ctx, req)
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	WriteSuccess(ctx, w, res, "success", ResponseMeta{HTTPStatus: http.StatusOK})
+}
+
+func (handler *Handler) RevokeApiKey(w http.ResponseWriter, r *http.Request) {
+	ctx, span := signoz.StartSpan(r.Context(), "handler.RevokeApiKey")
+	defer span.Finish()
+
+	idTokenClaims := auth.GetAuthFromCtx(ctx)
+	if idTokenClaims.UserID == 0 {
+		WriteError(ctx, w, lib.ErrorUnauthorized)
+		return
+	}
+
+	id, err := getParamUint(r, "ID")
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	err = handler.App.Usecase.RevokeApiKey(ctx, request.RevokeApiKey{
+		ID:     id,
+		UserID: idTokenClaims.UserID,
+	})
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	WriteSuccess(ctx, w, nil, "success", ResponseMeta{HTTPStatus: http.StatusOK})
+}
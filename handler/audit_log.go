@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"app/lib/signoz"
+	"app/request"
+	"net/http"
+)
+
+func (handler *Handler) GetAuditLogs(w http.ResponseWriter, r *http.Request) {
+	ctx, span := signoz.StartSpan(r.Context(), "handler.GetAuditLogs")
+	defer span.Finish()
+
+	req := request.GetAuditLogs{}
+	extractor := URLQueryExtractor{Request: r}
+	mapDataFunc := map[string]func(string) (any, error){
+		"limit": extractor.ExtractNumber,
+		"page":  extractor.ExtractNumber,
+		"sort":  extractor.ExtractSliceStringWithComma,
+	}
+
+	err := extractor.ExtractData(mapDataFunc, &req)
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	mapFilterField := map[string]FilterField{
+		"actor_id": {
+			Operators: []string{request.FilterOperatorEq, request.FilterOperatorIn},
+			Extract:   extractor.ExtractNumber,
+		},
+		"action": {
+			Operators: []string{request.FilterOperatorEq, request.FilterOperatorIn},
+			Extract:   extractor.ExtractString,
+		},
+		"target_type": {
+			Operators: []string{request.FilterOperatorEq},
+			Extract:   extractor.ExtractString,
+		},
+		"target_id": {
+			Operators: []string{request.FilterOperatorEq, request.FilterOperatorIn},
+			Extract:   extractor.ExtractNumber,
+		},
+		"ip_address": {
+			Operators: []string{request.FilterOperatorEq},
+			Extract:   extractor.ExtractString,
+		},
+		"request_id": {
+			Operators: []string{request.FilterOperatorEq},
+			Extract:   extractor.ExtractString,
+		},
+		"created_at": {
+			Operators: []string{request.FilterOperatorGte, request.FilterOperatorLte, request.FilterOperatorBetween},
+			Extract:   extractor.ExtractDate,
+		},
+	}
+
+	req.Filters, err = extractor.ExtractFilters(mapFilterField)
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	res, err := handler.App.Usecase.GetAuditLogs(ctx, req)
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	meta := ResponseMeta{HTTPStatus: http.StatusOK}
+	meta.SerializeFromResponse(res.BasePaginateResponse)
+	WriteSuccess(ctx, w, res.Data, "success", meta)
+}
@@ -5,7 +5,10 @@ import (
 	"app/lib/auth"
 	"app/lib/signoz"
 	"app/request"
+	"encoding/json"
 	"net/http"
+
+	"github.com/go-chi/chi/v5"
 )
 
 func (handler *Handler) Register(w http.ResponseWriter, r *http.Request) {
@@ -155,8 +158,78 @@ func (handler *Handler) ValidateMfaOtp(w http.ResponseWriter, r *http.Request) {
 	}
 
 	res, err := handler.App.Usecase.ValidateOtp(ctx, request.ValidateMfaOtp{
-		OtpCode: req.OtpCode,
-		UserId:  idTokenClaims.UserID,
+		OtpCode:      req.OtpCode,
+		RecoveryCode: req.RecoveryCode,
+		UserId:       idTokenClaims.UserID,
+	})
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	WriteSuccess(ctx, w, res, "success", ResponseMeta{HTTPStatus: http.StatusOK})
+}
+
+func (handler *Handler) EnrollMfaTotp(w http.ResponseWriter, r *http.Request) {
+	ctx, span := signoz.StartSpan(r.Context(), "handler.EnrollMfaTotp")
+	defer span.Finish()
+
+	idTokenClaims := auth.GetAuthFromCtx(ctx)
+	if idTokenClaims.UserID == 0 {
+		WriteError(ctx, w, lib.ErrorUnauthorized)
+		return
+	}
+
+	res, err := handler.App.Usecase.EnrollTotp(ctx, request.EnrollTotp{
+		UserID: idTokenClaims.UserID,
+	})
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	WriteSuccess(ctx, w, res, "success", ResponseMeta{HTTPStatus: http.StatusOK})
+}
+
+func (handler *Handler) ConfirmMfaTotp(w http.ResponseWriter, r *http.Request) {
+	ctx, span := signoz.StartSpan(r.Context(), "handler.ConfirmMfaTotp")
+	defer span.Finish()
+
+	idTokenClaims := auth.GetAuthFromCtx(ctx)
+	if idTokenClaims.UserID == 0 {
+		WriteError(ctx, w, lib.ErrorUnauthorized)
+		return
+	}
+
+	req := request.ConfirmTotp{}
+	err := decodeAndValidateRequest(r, &req)
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+	req.UserID = idTokenClaims.UserID
+
+	res, err := handler.App.Usecase.ConfirmTotp(ctx, req)
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	WriteSuccess(ctx, w, res, "success", ResponseMeta{HTTPStatus: http.StatusOK})
+}
+
+func (handler *Handler) RegenerateMfaRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	ctx, span := signoz.StartSpan(r.Context(), "handler.RegenerateMfaRecoveryCodes")
+	defer span.Finish()
+
+	idTokenClaims := auth.GetAuthFromCtx(ctx)
+	if idTokenClaims.UserID == 0 {
+		WriteError(ctx, w, lib.ErrorUnauthorized)
+		return
+	}
+
+	res, err := handler.App.Usecase.RegenerateRecoveryCodes(ctx, request.RegenerateRecoveryCodes{
+		UserID: idTokenClaims.UserID,
 	})
 	if err != nil {
 		WriteError(ctx, w, err)
@@ -186,6 +259,46 @@ func (handler *Handler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
 	WriteSuccess(ctx, w, nil, "success", ResponseMeta{HTTPStatus: http.StatusOK})
 }
 
+func (handler *Handler) SendMagicLink(w http.ResponseWriter, r *http.Request) {
+	ctx, span := signoz.StartSpan(r.Context(), "handler.SendMagicLink")
+	defer span.Finish()
+
+	req := request.SendMagicLink{}
+	err := decodeAndValidateRequest(r, &req)
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	err = handler.App.Usecase.SendMagicLink(ctx, req)
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	WriteSuccess(ctx, w, nil, "success", ResponseMeta{HTTPStatus: http.StatusOK})
+}
+
+func (handler *Handler) VerifyMagicLink(w http.ResponseWriter, r *http.Request) {
+	ctx, span := signoz.StartSpan(r.Context(), "handler.VerifyMagicLink")
+	defer span.Finish()
+
+	req := request.VerifyMagicLink{}
+	err := decodeAndValidateRequest(r, &req)
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	res, err := handler.App.Usecase.VerifyMagicLink(ctx, req)
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	WriteSuccess(ctx, w, res, "success", ResponseMeta{HTTPStatus: http.StatusOK})
+}
+
 func (handler *Handler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 	ctx, span := signoz.StartSpan(r.Context(), "handler.ResetPassword")
 	defer span.Finish()
@@ -206,18 +319,41 @@ func (handler *Handler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 	WriteSuccess(ctx, w, nil, "success", ResponseMeta{HTTPStatus: http.StatusOK})
 }
 
-func (handler *Handler) SsoGoogle(w http.ResponseWriter, r *http.Request) {
-	ctx, span := signoz.StartSpan(r.Context(), "handler.SsoGoogle")
+func (handler *Handler) SsoLogin(w http.ResponseWriter, r *http.Request) {
+	ctx, span := signoz.StartSpan(r.Context(), "handler.SsoLogin")
 	defer span.Finish()
 
-	req := request.SsoGoogle{}
+	req := request.SsoLogin{}
 	err := decodeAndValidateRequest(r, &req)
 	if err != nil {
 		WriteError(ctx, w, err)
 		return
 	}
+	req.Provider = chi.URLParam(r, "provider")
+
+	res, err := handler.App.Usecase.SsoLogin(ctx, req)
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	WriteSuccess(ctx, w, res, "success", ResponseMeta{HTTPStatus: http.StatusOK})
+}
+
+func (handler *Handler) GetSessions(w http.ResponseWriter, r *http.Request) {
+	ctx, span := signoz.StartSpan(r.Context(), "handler.GetSessions")
+	defer span.Finish()
+
+	idTokenClaims := auth.GetAuthFromCtx(ctx)
+	if idTokenClaims.UserID == 0 {
+		WriteError(ctx, w, lib.ErrorUnauthorized)
+		return
+	}
 
-	res, err := handler.App.Usecase.SsoGoogle(ctx, req)
+	res, err := handler.App.Usecase.GetSessions(ctx, request.GetSessions{
+		UserID:      idTokenClaims.UserID,
+		AccessToken: auth.GetAccessTokenFromCtx(ctx),
+	})
 	if err != nil {
 		WriteError(ctx, w, err)
 		return
@@ -225,3 +361,99 @@ func (handler *Handler) SsoGoogle(w http.ResponseWriter, r *http.Request) {
 
 	WriteSuccess(ctx, w, res, "success", ResponseMeta{HTTPStatus: http.StatusOK})
 }
+
+func (handler *Handler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	ctx, span := signoz.StartSpan(r.Context(), "handler.RevokeSession")
+	defer span.Finish()
+
+	idTokenClaims := auth.GetAuthFromCtx(ctx)
+	if idTokenClaims.UserID == 0 {
+		WriteError(ctx, w, lib.ErrorUnauthorized)
+		return
+	}
+
+	id, err := getParamUint(r, "ID")
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	err = handler.App.Usecase.RevokeSession(ctx, request.RevokeSession{
+		ID:     id,
+		UserID: idTokenClaims.UserID,
+	})
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	WriteSuccess(ctx, w, nil, "success", ResponseMeta{HTTPStatus: http.StatusOK})
+}
+
+func (handler *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	ctx, span := signoz.StartSpan(r.Context(), "handler.Logout")
+	defer span.Finish()
+
+	idTokenClaims := auth.GetAuthFromCtx(ctx)
+	if idTokenClaims.UserID == 0 {
+		WriteError(ctx, w, lib.ErrorUnauthorized)
+		return
+	}
+
+	// Request body is optional
+	req := request.Logout{}
+	if r.ContentLength != 0 {
+		err := decodeAndValidateRequest(r, &req)
+		if err != nil {
+			WriteError(ctx, w, err)
+			return
+		}
+	}
+	req.UserID = idTokenClaims.UserID
+	req.AccessToken = auth.GetAccessTokenFromCtx(ctx)
+
+	err := handler.App.Usecase.Logout(ctx, req)
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	WriteSuccess(ctx, w, nil, "success", ResponseMeta{HTTPStatus: http.StatusOK})
+}
+
+func (handler *Handler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	ctx, span := signoz.StartSpan(r.Context(), "handler.LogoutAll")
+	defer span.Finish()
+
+	idTokenClaims := auth.GetAuthFromCtx(ctx)
+	if idTokenClaims.UserID == 0 {
+		WriteError(ctx, w, lib.ErrorUnauthorized)
+		return
+	}
+
+	err := handler.App.Usecase.LogoutAll(ctx, request.LogoutAll{
+		UserID: idTokenClaims.UserID,
+	})
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	WriteSuccess(ctx, w, nil, "success", ResponseMeta{HTTPStatus: http.StatusOK})
+}
+
+func (handler *Handler) GetJwks(w http.ResponseWriter, r *http.Request) {
+	ctx, span := signoz.StartSpan(r.Context(), "handler.GetJwks")
+	defer span.Finish()
+
+	res, err := handler.App.Usecase.GetJwks(ctx)
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	// Written as a plain JWK Set document, jwks consumers do not understand the success body envelope
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	_ = json.NewEncoder(w).Encode(res)
+}
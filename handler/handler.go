@@ -11,6 +11,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -46,12 +48,14 @@ type ErrorBody struct {
 }
 
 type ResponseMeta struct {
-	HTTPStatus int   `json:"http_status"`
-	Total      *uint `json:"total,omitempty"`
-	Offset     *uint `json:"offset,omitempty"`
-	Limit      *uint `json:"limit,omitempty"`
-	Page       *uint `json:"page,omitempty"`
-	LastPage   *uint `json:"last_page,omitempty"`
+	HTTPStatus int     `json:"http_status"`
+	Total      *uint   `json:"total,omitempty"`
+	Offset     *uint   `json:"offset,omitempty"`
+	Limit      *uint   `json:"limit,omitempty"`
+	Page       *uint   `json:"page,omitempty"`
+	LastPage   *uint   `json:"last_page,omitempty"`
+	NextCursor *string `json:"next_cursor,omitempty"`
+	PrevCursor *string `json:"prev_cursor,omitempty"`
 }
 
 type ErrorInfo struct {
@@ -127,6 +131,11 @@ func WriteSuccess(ctx context.Context, w http.ResponseWriter, data any, message
 }
 
 func (handler *ResponseMeta) SerializeFromResponse(resp response.BasePaginateResponse) {
+	if resp.IsCursor {
+		handler.serializeCursorFromResponse(resp)
+		return
+	}
+
 	handler.Total = &resp.Total
 	if resp.Limit == 0 {
 		return
@@ -145,6 +154,19 @@ func (handler *ResponseMeta) SerializeFromResponse(resp response.BasePaginateRes
 	handler.LastPage = &lastPage
 }
 
+func (handler *ResponseMeta) serializeCursorFromResponse(resp response.BasePaginateResponse) {
+	if resp.HasTotal {
+		handler.Total = &resp.Total
+	}
+	handler.Limit = &resp.Limit
+	if resp.NextCursor != "" {
+		handler.NextCursor = &resp.NextCursor
+	}
+	if resp.PrevCursor != "" {
+		handler.PrevCursor = &resp.PrevCursor
+	}
+}
+
 type URLQueryExtractor struct {
 	Request *http.Request
 }
@@ -154,6 +176,16 @@ func (q *URLQueryExtractor) ExtractDate(s string) (any, error) {
 	return time.Parse("2006-01-02", s)
 }
 
+// ExtractDateEnd parse date with layout 2006-01-02 into the last microsecond of the day, so an inclusive upper bound
+// covers the whole day.
+func (q *URLQueryExtractor) ExtractDateEnd(s string) (any, error) {
+	date, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return nil, err
+	}
+	return date.AddDate(0, 0, 1).Add(-time.Microsecond), nil
+}
+
 // ExtractBool will parse data with function strconv.ParseBool().
 func (q *URLQueryExtractor) ExtractBool(s string) (any, error) {
 	return strconv.ParseBool(s)
@@ -251,6 +283,95 @@ func (q *URLQueryExtractor) ExtractData(fn map[string]func(string) (val any, err
 	return nil
 }
 
+// FilterField declare a filterable field, only the listed operators are accepted and every value is parsed with Extract
+type FilterField struct {
+	Operators  []string
+	Extract    func(string) (any, error)
+	ExtractEnd func(string) (any, error) // Optional, parse the upper bound of lte and between instead of Extract
+}
+
+// ExtractFilters parse the filters of the whitelisted fields from the query string with format field[operator]=value,
+// field=value is parsed as the eq operator. The values of in and between are separated by comma, is_null expects a bool
+func (q *URLQueryExtractor) ExtractFilters(fn map[string]FilterField) ([]request.Filter, error) {
+	urlQuery := q.Request.URL.Query()
+	filters := []request.Filter{}
+
+	parseQueryError := lib.ErrorParseQuery
+	parseQueryError.ErrDetails = map[string]any{}
+
+	keys := []string{}
+	for key := range urlQuery {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		field, operator := parseFilterKey(key)
+		filterField, ok := fn[field]
+		if !ok || urlQuery.Get(key) == "" {
+			continue
+		}
+
+		if !slices.Contains(filterField.Operators, operator) {
+			parseQueryError.ErrDetails[key] = fmt.Sprintf("operator %s is not allowed", operator)
+			continue
+		}
+
+		rawValues := []string{urlQuery.Get(key)}
+		if operator == request.FilterOperatorIn || operator == request.FilterOperatorBetween {
+			rawValues = strings.Split(urlQuery.Get(key), ",")
+		}
+		if operator == request.FilterOperatorBetween && len(rawValues) != 2 {
+			parseQueryError.ErrDetails[key] = "between expects two values separated by comma"
+			continue
+		}
+
+		extract := filterField.Extract
+		if operator == request.FilterOperatorIsNull {
+			extract = q.ExtractBool
+		}
+
+		values := []any{}
+		for i, rawValue := range rawValues {
+			extractValue := extract
+			isUpperBound := operator == request.FilterOperatorLte || (operator == request.FilterOperatorBetween && i == 1)
+			if isUpperBound && filterField.ExtractEnd != nil {
+				extractValue = filterField.ExtractEnd
+			}
+
+			value, err := extractValue(strings.TrimSpace(rawValue))
+			if err != nil {
+				parseQueryError.ErrDetails[key] = err.Error()
+				break
+			}
+			values = append(values, value)
+		}
+		if _, ok := parseQueryError.ErrDetails[key]; ok {
+			continue
+		}
+
+		filters = append(filters, request.Filter{
+			Field:    field,
+			Operator: operator,
+			Values:   values,
+		})
+	}
+	if len(parseQueryError.ErrDetails) > 0 {
+		return nil, parseQueryError
+	}
+
+	return filters, nil
+}
+
+// parseFilterKey split a query key such as created_at[gte] into its field and operator
+func parseFilterKey(key string) (field, operator string) {
+	field, operator, found := strings.Cut(key, "[")
+	if !found {
+		return key, request.FilterOperatorEq
+	}
+	return field, strings.TrimSuffix(operator, "]")
+}
+
 func getParamUint(r *http.Request, key string) (uint, error) {
 	valueString := chi.URLParam(r, key)
 	value, err := strconv.ParseUint(valueString, 10, 64)
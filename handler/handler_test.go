@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"app/lib"
+	"app/request"
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExtractFilters(t *testing.T) {
+	extractor := &URLQueryExtractor{}
+	fn := map[string]FilterField{
+		"name": {
+			Operators: []string{request.FilterOperatorEq, request.FilterOperatorIn},
+			Extract:   extractor.ExtractString,
+		},
+		"age": {
+			Operators: []string{request.FilterOperatorGte, request.FilterOperatorBetween},
+			Extract:   extractor.ExtractNumber,
+		},
+		"deleted_at": {
+			Operators: []string{request.FilterOperatorIsNull},
+			Extract:   extractor.ExtractDate,
+		},
+		"created_at": {
+			Operators:  []string{request.FilterOperatorGte, request.FilterOperatorLte, request.FilterOperatorBetween},
+			Extract:    extractor.ExtractDate,
+			ExtractEnd: extractor.ExtractDateEnd,
+		},
+	}
+	startOfDay := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	endOfDay := time.Date(2026, 1, 31, 23, 59, 59, 999999000, time.UTC)
+
+	tests := []struct {
+		name        string
+		query       url.Values
+		want        []request.Filter
+		wantErrKeys []string
+	}{
+		{
+			name:  "shorthand eq",
+			query: url.Values{"name": {"john"}},
+			want:  []request.Filter{{Field: "name", Operator: request.FilterOperatorEq, Values: []any{"john"}}},
+		},
+		{
+			name:  "in split by comma",
+			query: url.Values{"name[in]": {"john, jane"}},
+			want:  []request.Filter{{Field: "name", Operator: request.FilterOperatorIn, Values: []any{"john", "jane"}}},
+		},
+		{
+			name:  "between",
+			query: url.Values{"age[between]": {"18,30"}},
+			want:  []request.Filter{{Field: "age", Operator: request.FilterOperatorBetween, Values: []any{int64(18), int64(30)}}},
+		},
+		{
+			name:  "is null is parsed as bool",
+			query: url.Values{"deleted_at[is_null]": {"true"}},
+			want:  []request.Filter{{Field: "deleted_at", Operator: request.FilterOperatorIsNull, Values: []any{true}}},
+		},
+		{
+			name:  "date lower bound start at midnight",
+			query: url.Values{"created_at[gte]": {"2026-01-01"}},
+			want:  []request.Filter{{Field: "created_at", Operator: request.FilterOperatorGte, Values: []any{startOfDay}}},
+		},
+		{
+			name:  "date upper bound cover the whole day",
+			query: url.Values{"created_at[lte]": {"2026-01-31"}},
+			want:  []request.Filter{{Field: "created_at", Operator: request.FilterOperatorLte, Values: []any{endOfDay}}},
+		},
+		{
+			name:  "date between cover the whole last day",
+			query: url.Values{"created_at[between]": {"2026-01-01,2026-01-31"}},
+			want:  []request.Filter{{Field: "created_at", Operator: request.FilterOperatorBetween, Values: []any{startOfDay, endOfDay}}},
+		},
+		{
+			name:  "sorted by key",
+			query: url.Values{"name": {"john"}, "age[gte]": {"18"}},
+			want: []request.Filter{
+				{Field: "age", Operator: request.FilterOperatorGte, Values: []any{int64(18)}},
+				{Field: "name", Operator: request.FilterOperatorEq, Values: []any{"john"}},
+			},
+		},
+		{
+			name:  "unknown field and empty value are skipped",
+			query: url.Values{"password": {"secret"}, "name": {""}, "page": {"2"}},
+			want:  []request.Filter{},
+		},
+		{
+			name:        "operator not allowed",
+			query:       url.Values{"name[gte]": {"john"}},
+			wantErrKeys: []string{"name[gte]"},
+		},
+		{
+			name:        "between without two values",
+			query:       url.Values{"age[between]": {"18"}},
+			wantErrKeys: []string{"age[between]"},
+		},
+		{
+			name:        "invalid values",
+			query:       url.Values{"age[gte]": {"old"}, "deleted_at[is_null]": {"maybe"}},
+			wantErrKeys: []string{"age[gte]", "deleted_at[is_null]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &URLQueryExtractor{Request: httptest.NewRequest("GET", "/?"+tt.query.Encode(), nil)}
+			got, err := q.ExtractFilters(fn)
+
+			if len(tt.wantErrKeys) > 0 {
+				var customError lib.CustomError
+				if !errors.As(err, &customError) || customError.Code != lib.ErrorParseQuery.Code {
+					t.Fatalf("err = %v, want parse query error", err)
+				}
+				for _, key := range tt.wantErrKeys {
+					if _, ok := customError.ErrDetails[key]; !ok {
+						t.Errorf("err details = %v, want key %s", customError.ErrDetails, key)
+					}
+				}
+				if len(customError.ErrDetails) != len(tt.wantErrKeys) {
+					t.Errorf("err details = %v, want %d keys", customError.ErrDetails, len(tt.wantErrKeys))
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("err = %v, want nil", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filters = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"app/lib"
+	"app/lib/auth"
+	"app/lib/signoz"
+	"app/request"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (handler *Handler) GetJob(w http.ResponseWriter, r *http.Request) {
+	ctx, span := signoz.StartSpan(r.Context(), "handler.GetJob")
+	defer span.Finish()
+
+	idTokenClaims := auth.GetAuthFromCtx(ctx)
+	if idTokenClaims.UserID == 0 {
+		WriteError(ctx, w, lib.ErrorUnauthorized)
+		return
+	}
+
+	res, err := handler.App.Usecase.GetJob(ctx, request.GetJob{
+		ID:     chi.URLParam(r, "ID"),
+		UserID: idTokenClaims.UserID,
+	})
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	WriteSuccess(ctx, w, res, "success", ResponseMeta{HTTPStatus: http.StatusOK})
+}
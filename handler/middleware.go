@@ -3,15 +3,21 @@ package handler
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
+	"net"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"app/lib"
 	"app/lib/auth"
+	"app/lib/constant"
 	"app/lib/logger"
 	"app/lib/signoz"
 	"app/request"
@@ -81,12 +87,13 @@ func (handler *Handler) InstrumentMiddleware(next http.Handler) http.Handler {
 							reqBodyFormMap[key] = value
 						}
 					}
+					redactFields(reqBodyFormMap)
 
 					reqBodyFormJson, _ := json.Marshal(reqBodyFormMap)
 					reqBodyForm = string(reqBodyFormJson)
 				}
 			} else {
-				reqBodyJson = reqBody
+				reqBodyJson = redactRequestBody(reqBody)
 			}
 		}
 
@@ -102,6 +109,10 @@ func (handler *Handler) InstrumentMiddleware(next http.Handler) http.Handler {
 		}
 
 		ctx = context.WithValue(request.Context(), logger.CtxRequestID, reqID)
+		ctx = auth.NewDeviceFromCtx(ctx, auth.Device{
+			UserAgent: request.UserAgent(),
+			IPAddress: handler.getClientIP(request),
+		})
 		m := httpsnoop.CaptureMetrics(handler.PanicMiddleware(next), writer, request.WithContext(ctx))
 
 		var signozSpan trace.Span = *span.SignozSpan
@@ -162,7 +173,13 @@ func (handler *Handler) AuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
+		accessToken, _ := getBearerToken(request)
+		if accessToken != "" {
+			handler.App.Usecase.TouchSession(ctx, accessToken)
+		}
+
 		ctx = auth.NewFromCtx(ctx, idTokenClaim)
+		ctx = auth.NewAccessTokenFromCtx(ctx, accessToken)
 		next.ServeHTTP(writer, request.WithContext(ctx))
 	})
 }
@@ -182,6 +199,52 @@ func (handler *Handler) AuthMfaMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// RejectApiKeyMiddleware reject request authenticated with a personal api key or an api client token, used on the routes
+// managing the account and its credentials so a leaked or narrowly scoped key can't be escalated and a client token
+// without user never reach them. It must be used after AuthMiddleware
+func (handler *Handler) RejectApiKeyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		ctx := request.Context()
+
+		idTokenClaim := auth.GetAuthFromCtx(ctx)
+		if idTokenClaim == nil {
+			WriteError(ctx, writer, lib.ErrorUnauthorized)
+			return
+		}
+		if idTokenClaim.IsApiKey || idTokenClaim.ClientID != "" || idTokenClaim.UserID == 0 {
+			WriteError(ctx, writer, lib.ErrorForbidden)
+			return
+		}
+
+		next.ServeHTTP(writer, request)
+	})
+}
+
+// RequirePermission only allow authenticated request which claims contain all of the given permissions,
+// api client tokens are checked against their scopes instead. It must be used after AuthMiddleware
+func (handler *Handler) RequirePermission(permissions ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			ctx := request.Context()
+
+			idTokenClaim := auth.GetAuthFromCtx(ctx)
+			if idTokenClaim == nil {
+				WriteError(ctx, writer, lib.ErrorUnauthorized)
+				return
+			}
+
+			for _, permission := range permissions {
+				if !idTokenClaim.HasPermission(permission) && !idTokenClaim.HasScope(permission) {
+					WriteError(ctx, writer, lib.ErrorForbidden)
+					return
+				}
+			}
+
+			next.ServeHTTP(writer, request)
+		})
+	}
+}
+
 func (handler *Handler) WebSocketAuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
 		ctx := request.Context()
@@ -254,23 +317,34 @@ func (h *Handler) BasicAuthMiddleware(next http.Handler) http.Handler {
 }
 
 func (handler *Handler) getAndValidateIDToken(ctx context.Context, request *http.Request) (*auth.IDTokenClaims, error) {
-	headerAuthorization := request.Header.Get("Authorization")
-	if headerAuthorization == "" {
-		return nil, lib.ErrorUnauthorized
+	apiKey := getApiKey(request)
+	if apiKey != "" {
+		idTokenClaim, err := handler.App.Usecase.AuthenticateApiKey(ctx, apiKey)
+		if err != nil {
+			return nil, lib.ErrorUnauthorized
+		}
+		return idTokenClaim, nil
 	}
 
-	splitToken := strings.Split(headerAuthorization, " ")
-	if len(splitToken) != 2 || splitToken[0] != "Bearer" {
-		return nil, lib.ErrorUnauthorized
+	accessToken, err := getBearerToken(request)
+	if err != nil {
+		return nil, err
 	}
 
 	// Exchange access_token -> id_token
-	accessToken := splitToken[1]
 	idTokenClaim, err := handler.validateIDToken(ctx, accessToken)
 	if err != nil {
 		return nil, lib.ErrorUnauthorized
 	}
 
+	// Api client tokens are not tracked per client, so a deleted or deactivated client is rejected here
+	if idTokenClaim.ClientID != "" {
+		err = handler.App.Usecase.ValidateApiClient(ctx, idTokenClaim.ClientID)
+		if err != nil {
+			return nil, lib.ErrorUnauthorized
+		}
+	}
+
 	return idTokenClaim, nil
 }
 
@@ -288,6 +362,65 @@ func (handler *Handler) validateIDToken(ctx context.Context, accessToken string)
 	return idTokenClaim, nil
 }
 
+func getBearerToken(request *http.Request) (string, error) {
+	headerAuthorization := request.Header.Get("Authorization")
+	if headerAuthorization == "" {
+		return "", lib.ErrorUnauthorized
+	}
+
+	splitToken := strings.Split(headerAuthorization, " ")
+	if len(splitToken) != 2 || splitToken[0] != "Bearer" {
+		return "", lib.ErrorUnauthorized
+	}
+
+	return splitToken[1], nil
+}
+
+// getApiKey get the personal api key from X-API-Key header or the ApiKey authorization scheme
+func getApiKey(request *http.Request) string {
+	apiKey := request.Header.Get(constant.ApiKeyHeader)
+	if apiKey != "" {
+		return apiKey
+	}
+
+	splitToken := strings.Split(request.Header.Get("Authorization"), " ")
+	if len(splitToken) == 2 && splitToken[0] == constant.ApiKeyAuthScheme {
+		return splitToken[1]
+	}
+
+	return ""
+}
+
+// getClientIP get the client ip address, the proxy headers are only honoured when the remote address is a trusted proxy.
+// X-Forwarded-For is read from the right, the first address which is not a trusted proxy is the client
+func (handler *Handler) getClientIP(request *http.Request) string {
+	remoteIP, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		remoteIP = request.RemoteAddr
+	}
+	if !handler.App.Usecase.IsTrustedProxy(remoteIP) {
+		return remoteIP
+	}
+
+	forwardedFor := request.Header.Get("X-Forwarded-For")
+	if forwardedFor != "" {
+		forwardedIPs := strings.Split(forwardedFor, ",")
+		for i := len(forwardedIPs) - 1; i >= 0; i-- {
+			forwardedIP := strings.TrimSpace(forwardedIPs[i])
+			if i == 0 || !handler.App.Usecase.IsTrustedProxy(forwardedIP) {
+				return forwardedIP
+			}
+		}
+	}
+
+	realIP := strings.TrimSpace(request.Header.Get("X-Real-IP"))
+	if realIP != "" {
+		return realIP
+	}
+
+	return remoteIP
+}
+
 func generateTransactionNameFromURLPath(s string) string {
 	parts := strings.Split(s, "/")
 	result := "home"
@@ -325,3 +458,109 @@ func isAuthPath(urlPath string) bool {
 
 	return false
 }
+
+// redactedFields are the request body fields which are never written to the spans and traffic logs
+var redactedFields = map[string]bool{
+	"password":             true,
+	"current_password":     true,
+	"new_password":         true,
+	"confirm_new_password": true,
+}
+
+// redactRequestBody mask the redacted fields of a json request body, a body which is not a json object is kept as is
+func redactRequestBody(body string) string {
+	fields := map[string]any{}
+	err := json.Unmarshal([]byte(body), &fields)
+	if err != nil {
+		return body
+	}
+
+	redactFields(fields)
+	redactedBody, _ := json.Marshal(fields)
+	return string(redactedBody)
+}
+
+func redactFields(fields map[string]any) {
+	for key, value := range fields {
+		if redactedFields[key] {
+			fields[key] = "[REDACTED]"
+			continue
+		}
+		if nested, ok := value.(map[string]any); ok {
+			redactFields(nested)
+		}
+	}
+}
+
+// RateLimitKeyFunc resolve the identifier a request is counted against, an empty identifier falls back to the client ip
+type RateLimitKeyFunc func(r *http.Request) string
+
+func (handler *Handler) RateLimitByIP(r *http.Request) string {
+	return auth.GetDeviceFromCtx(r.Context()).IPAddress
+}
+
+// RateLimitByUser must be placed after AuthMiddleware so the id token claims are available,
+// api client tokens have no user so they are counted by client
+func (handler *Handler) RateLimitByUser(r *http.Request) string {
+	idTokenClaims := auth.GetAuthFromCtx(r.Context())
+	if idTokenClaims == nil {
+		return ""
+	}
+	if idTokenClaims.ClientID != "" {
+		return "client:" + idTokenClaims.ClientID
+	}
+	if idTokenClaims.UserID == 0 {
+		return ""
+	}
+	return fmt.Sprintf("user:%d", idTokenClaims.UserID)
+}
+
+// RateLimitByAPIKey use the hash of the api key, so the raw key never lands in redis.
+// Request without api key are counted by user, so each key has its own budget apart from the user sessions
+func (handler *Handler) RateLimitByAPIKey(r *http.Request) string {
+	apiKey := getApiKey(r)
+	if apiKey == "" {
+		return handler.RateLimitByUser(r)
+	}
+	return fmt.Sprintf("api-key:%x", sha256.Sum256([]byte(apiKey)))
+}
+
+// RateLimitMiddleware limit the requests of each identifier to limit requests per sliding window,
+// the limiter name separate the counters between route groups
+func (handler *Handler) RateLimitMiddleware(limiterName string, limit int, window time.Duration, keyFunc RateLimitKeyFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			identifier := keyFunc(r)
+			if identifier == "" {
+				identifier = handler.RateLimitByIP(r)
+			}
+
+			res, err := handler.App.Usecase.HitRateLimit(ctx, request.HitRateLimit{
+				LimiterName: limiterName,
+				Identifier:  identifier,
+				Limit:       limit,
+				Window:      window,
+			})
+			customError, ok := err.(lib.CustomError)
+			if err != nil && (!ok || customError.Code != lib.ErrorRateLimit.Code) {
+				// Fail open, an unavailable cache should not take the endpoints down with it
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			resetAfter := strconv.Itoa(int(math.Ceil(res.ResetAfter.Seconds())))
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			w.Header().Set("X-RateLimit-Reset", resetAfter)
+			if err != nil {
+				w.Header().Set("Retry-After", resetAfter)
+				WriteError(ctx, w, err)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
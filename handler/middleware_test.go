@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestRedactRequestBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want map[string]any
+	}{
+		{
+			name: "change email",
+			body: `{"new_email":"john@example.com","current_password":"secret"}`,
+			want: map[string]any{"new_email": "john@example.com", "current_password": "[REDACTED]"},
+		},
+		{
+			name: "change password",
+			body: `{"current_password":"secret","new_password":"new secret","confirm_new_password":"new secret"}`,
+			want: map[string]any{"current_password": "[REDACTED]", "new_password": "[REDACTED]", "confirm_new_password": "[REDACTED]"},
+		},
+		{
+			name: "delete account",
+			body: `{"password":"secret"}`,
+			want: map[string]any{"password": "[REDACTED]"},
+		},
+		{
+			name: "nested",
+			body: `{"user":{"name":"john","password":"secret"}}`,
+			want: map[string]any{"user": map[string]any{"name": "john", "password": "[REDACTED]"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[string]any{}
+			if err := json.Unmarshal([]byte(redactRequestBody(tt.body)), &got); err != nil {
+				t.Fatalf("unmarshal body: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("body = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactRequestBodyNotJson(t *testing.T) {
+	body := "not a json object"
+	if got := redactRequestBody(body); got != body {
+		t.Errorf("body = %q, want %q", got, body)
+	}
+}
@@ -13,10 +13,13 @@ func (handler *Handler) GetUsers(w http.ResponseWriter, r *http.Request) {
 	req := request.GetUsers{}
 	extractor := URLQueryExtractor{Request: r}
 	mapDataFunc := map[string]func(string) (any, error){
-		"limit":  extractor.ExtractNumber,
-		"page":   extractor.ExtractNumber,
-		"search": extractor.ExtractString,
-		"sort":   extractor.ExtractSliceStringWithComma,
+		"limit":      extractor.ExtractNumber,
+		"page":       extractor.ExtractNumber,
+		"search":     extractor.ExtractString,
+		"sort":       extractor.ExtractSliceStringWithComma,
+		"pagination": extractor.ExtractString,
+		"cursor":     extractor.ExtractString,
+		"with_total": extractor.ExtractBool,
 	}
 
 	err := extractor.ExtractData(mapDataFunc, &req)
@@ -25,6 +28,12 @@ func (handler *Handler) GetUsers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	req.Filters, err = extractUserFilters(extractor)
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
 	res, err := handler.App.Usecase.GetUsers(ctx, req)
 	if err != nil {
 		WriteError(ctx, w, err)
@@ -124,3 +133,65 @@ func (handler *Handler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 
 	WriteSuccess(ctx, w, nil, "success", ResponseMeta{HTTPStatus: http.StatusOK})
 }
+
+func (handler *Handler) DeactivateUser(w http.ResponseWriter, r *http.Request) {
+	ctx, span := signoz.StartSpan(r.Context(), "handler.DeactivateUser")
+	defer span.Finish()
+
+	req := request.DeactivateUser{}
+	id, err := getParamUint(r, "ID")
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+	req.ID = id
+
+	err = handler.App.Usecase.DeactivateUser(ctx, req)
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	WriteSuccess(ctx, w, nil, "success", ResponseMeta{HTTPStatus: http.StatusOK})
+}
+
+func (handler *Handler) ReactivateUser(w http.ResponseWriter, r *http.Request) {
+	ctx, span := signoz.StartSpan(r.Context(), "handler.ReactivateUser")
+	defer span.Finish()
+
+	req := request.ReactivateUser{}
+	id, err := getParamUint(r, "ID")
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+	req.ID = id
+
+	err = handler.App.Usecase.ReactivateUser(ctx, req)
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	WriteSuccess(ctx, w, nil, "success", ResponseMeta{HTTPStatus: http.StatusOK})
+}
+
+// extractUserFilters parse the filters shared by the user listing and export
+func extractUserFilters(extractor URLQueryExtractor) ([]request.Filter, error) {
+	mapFilterField := map[string]FilterField{
+		"is_active": {
+			Operators: []string{request.FilterOperatorEq},
+			Extract:   extractor.ExtractBool,
+		},
+		"is_verified": {
+			Operators: []string{request.FilterOperatorEq},
+			Extract:   extractor.ExtractBool,
+		},
+		"created_at": {
+			Operators:  []string{request.FilterOperatorGte, request.FilterOperatorLte, request.FilterOperatorBetween},
+			Extract:    extractor.ExtractDate,
+			ExtractEnd: extractor.ExtractDateEnd,
+		},
+	}
+	return extractor.ExtractFilters(mapFilterField)
+}
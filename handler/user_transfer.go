@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"app/lib/auth"
+	"app/lib/constant"
+	"app/lib/signoz"
+	"app/request"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (handler *Handler) ImportUsers(w http.ResponseWriter, r *http.Request) {
+	ctx, span := signoz.StartSpan(r.Context(), "handler.ImportUsers")
+	defer span.Finish()
+
+	f, err := request.ParseFile(
+		r,
+		constant.MapUploadFileProps[constant.UploadPathUserImport].FormValue,
+		constant.MapUploadFileProps[constant.UploadPathUserImport].MaxFilesize,
+		constant.MapUploadFileProps[constant.UploadPathUserImport].AllowedExtensions,
+	)
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	res, err := handler.App.Usecase.ImportUsers(ctx, request.ImportUsers{
+		FileData: f,
+		UserID:   auth.GetAuthFromCtx(ctx).UserID,
+	})
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	WriteSuccess(ctx, w, res, "success", ResponseMeta{HTTPStatus: http.StatusAccepted})
+}
+
+func (handler *Handler) ExportUsers(w http.ResponseWriter, r *http.Request) {
+	ctx, span := signoz.StartSpan(r.Context(), "handler.ExportUsers")
+	defer span.Finish()
+
+	req := request.ExportUsers{}
+	extractor := URLQueryExtractor{Request: r}
+	mapDataFunc := map[string]func(string) (any, error){
+		"search": extractor.ExtractString,
+		"sort":   extractor.ExtractSliceStringWithComma,
+		"format": extractor.ExtractString,
+	}
+
+	err := extractor.ExtractData(mapDataFunc, &req)
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	req.Filters, err = extractUserFilters(extractor)
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	err = req.Validate()
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+	req.UserID = auth.GetAuthFromCtx(ctx).UserID
+
+	res, err := handler.App.Usecase.ExportUsers(ctx, req)
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	WriteSuccess(ctx, w, res, "success", ResponseMeta{HTTPStatus: http.StatusAccepted})
+}
+
+func (handler *Handler) GetUserTransfer(w http.ResponseWriter, r *http.Request) {
+	ctx, span := signoz.StartSpan(r.Context(), "handler.GetUserTransfer")
+	defer span.Finish()
+
+	res, err := handler.App.Usecase.GetUserTransfer(ctx, request.GetUserTransfer{
+		ID: chi.URLParam(r, "ID"),
+	})
+	if err != nil {
+		WriteError(ctx, w, err)
+		return
+	}
+
+	WriteSuccess(ctx, w, res, "success", ResponseMeta{HTTPStatus: http.StatusOK})
+}
@@ -3,16 +3,35 @@ package auth
 import (
 	"app/lib/constant"
 	"app/lib/logger"
+	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image/png"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/pquerna/otp"
 	"github.com/pquerna/otp/totp"
 	"go.uber.org/zap"
 )
 
 type UserCtxKey struct{}
+type AccessTokenCtxKey struct{}
+type DeviceCtxKey struct{}
+
+// Device is the client metadata of the current request
+type Device struct {
+	UserAgent string `json:"user_agent"`
+	IPAddress string `json:"ip_address"`
+}
 
 // https://auth0.com/docs/secure/tokens/access-tokens#sample-access-token
 type AccessTokenClaims struct {
@@ -28,8 +47,25 @@ type AccessTokenClaims struct {
 // https://openid.net/specs/openid-connect-core-1_0.html#IDToken
 type IDTokenClaims struct {
 	jwt.RegisteredClaims
-	IsMfaToken bool `json:"is_mfa_token"`
-	UserID     uint `json:"user_id"`
+	IsMfaToken  bool     `json:"is_mfa_token"`
+	UserID      uint     `json:"user_id"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+	ClientID    string   `json:"client_id,omitempty"` // Only set for tokens issued to api clients
+	Scopes      []string `json:"scopes,omitempty"`
+	IsApiKey    bool     `json:"is_api_key,omitempty"` // Set when the request is authenticated with a personal api key
+}
+
+func (claims *IDTokenClaims) HasRole(role string) bool {
+	return slices.Contains(claims.Roles, role)
+}
+
+func (claims *IDTokenClaims) HasPermission(permission string) bool {
+	return slices.Contains(claims.Permissions, permission)
+}
+
+func (claims *IDTokenClaims) HasScope(scope string) bool {
+	return slices.Contains(claims.Scopes, scope)
 }
 
 func NewFromCtx(ctx context.Context, idTokenClaim *IDTokenClaims) context.Context {
@@ -43,6 +79,28 @@ func GetAuthFromCtx(ctx context.Context) *IDTokenClaims {
 	return nil
 }
 
+func NewAccessTokenFromCtx(ctx context.Context, accessToken string) context.Context {
+	return context.WithValue(ctx, AccessTokenCtxKey{}, accessToken)
+}
+
+func GetAccessTokenFromCtx(ctx context.Context) string {
+	if accessToken, ok := ctx.Value(AccessTokenCtxKey{}).(string); ok {
+		return accessToken
+	}
+	return ""
+}
+
+func NewDeviceFromCtx(ctx context.Context, device Device) context.Context {
+	return context.WithValue(ctx, DeviceCtxKey{}, device)
+}
+
+func GetDeviceFromCtx(ctx context.Context) Device {
+	if device, ok := ctx.Value(DeviceCtxKey{}).(Device); ok {
+		return device
+	}
+	return Device{}
+}
+
 func GenerateOtpSecret(ctx context.Context, userId uint, period int) (string, error) {
 	identifier := strconv.Itoa(int(userId))
 	secret, err := totp.Generate(totp.GenerateOpts{
@@ -85,6 +143,9 @@ func ValidateOtpCode(ctx context.Context, otpCode, secret string, period int) (b
 		Digits: 6,
 		Skew:   1,
 	})
+	if errors.Is(err, otp.ErrValidateInputInvalidLength) {
+		return false, nil
+	}
 	if err != nil {
 		logger.LogError(ctx, "totp.ValidateCustom", []zap.Field{
 			zap.Error(err),
@@ -95,3 +156,99 @@ func ValidateOtpCode(ctx context.Context, otpCode, secret string, period int) (b
 
 	return validateOtp, nil
 }
+
+// GenerateTotpKey generate authenticator app key, the key contains both the secret and the otpauth:// URI
+func GenerateTotpKey(ctx context.Context, accountName string) (*otp.Key, error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      constant.DefaultIssuer,
+		AccountName: accountName,
+		Period:      constant.TotpAppPeriod,
+		Digits:      6,
+	})
+	if err != nil {
+		logger.LogError(ctx, "totp.Generate", []zap.Field{
+			zap.Error(err),
+			zap.Strings("tags", []string{"auth", "GenerateTotpKey"}),
+		}...)
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// GenerateTotpQrCode render the otpauth:// URI of the key as base64 encoded PNG QR code
+func GenerateTotpQrCode(ctx context.Context, key *otp.Key, size int) (string, error) {
+	img, err := key.Image(size, size)
+	if err != nil {
+		logger.LogError(ctx, "key.Image", []zap.Field{
+			zap.Error(err),
+			zap.Strings("tags", []string{"auth", "GenerateTotpQrCode"}),
+		}...)
+		return "", err
+	}
+
+	buf := new(bytes.Buffer)
+	err = png.Encode(buf, img)
+	if err != nil {
+		logger.LogError(ctx, "png.Encode", []zap.Field{
+			zap.Error(err),
+			zap.Strings("tags", []string{"auth", "GenerateTotpQrCode"}),
+		}...)
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// GenerateRecoveryCodes generate random one-time recovery codes with format xxxxx-xxxxx
+func GenerateRecoveryCodes(ctx context.Context, count, length int) ([]string, error) {
+	const alphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+	codes := []string{}
+	for range count {
+		randomBytes := make([]byte, length)
+		_, err := rand.Read(randomBytes)
+		if err != nil {
+			logger.LogError(ctx, "rand.Read", []zap.Field{
+				zap.Error(err),
+				zap.Strings("tags", []string{"auth", "GenerateRecoveryCodes"}),
+			}...)
+			return nil, err
+		}
+
+		code := make([]byte, length)
+		for i, randomByte := range randomBytes {
+			code[i] = alphabet[int(randomByte)%len(alphabet)]
+		}
+		codes = append(codes, fmt.Sprintf("%s-%s", code[:length/2], code[length/2:]))
+	}
+
+	return codes, nil
+}
+
+// NormalizeRecoveryCode make user input comparable with the generated recovery code
+func NormalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
+
+// GenerateSecret generate url safe random secret from the given number of random bytes
+func GenerateSecret(ctx context.Context, size int) (string, error) {
+	randomBytes := make([]byte, size)
+	_, err := rand.Read(randomBytes)
+	if err != nil {
+		logger.LogError(ctx, "rand.Read", []zap.Field{
+			zap.Error(err),
+			zap.Strings("tags", []string{"auth", "GenerateSecret"}),
+		}...)
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(randomBytes), nil
+}
+
+// HashApiKey hash the api key with sha256, the key is random enough so it does not need a slow hash
+// and the hash can be used to look the key up
+func HashApiKey(apiKey string) string {
+	hash := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"app/lib/oidc"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey is one of the id token keys, tokens carry its Kid in the header so it can be verified after rotation
+type SigningKey struct {
+	Kid        string
+	Method     jwt.SigningMethod
+	PrivateKey crypto.Signer
+}
+
+// KeySet hold every key accepted for id token verification and the one used for signing new tokens.
+// Rotating a key is done by adding the new key, switching the signing kid, then removing the old key
+// once every token signed with it has expired
+type KeySet struct {
+	SigningKid string
+	Keys       map[string]*SigningKey
+	// HmacKey is the legacy shared secret, used to sign when no asymmetric key is configured
+	// and to verify tokens without kid header issued before the asymmetric keys were introduced.
+	// Leave it empty to reject every hmac token once the legacy tokens have expired
+	HmacKey []byte
+}
+
+// LoadSigningKey read a PKCS#8 (or PKCS#1 for RSA) PEM private key, the signing method follows the key type
+func LoadSigningKey(kid, path string) (*SigningKey, error) {
+	pemBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("no pem block found in %s", path)
+	}
+
+	var privateKey any
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		privateKey, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signingKey := &SigningKey{Kid: kid}
+	switch privateKey := privateKey.(type) {
+	case *rsa.PrivateKey:
+		signingKey.Method = jwt.SigningMethodRS256
+		signingKey.PrivateKey = privateKey
+	case *ecdsa.PrivateKey:
+		switch privateKey.Curve.Params().BitSize {
+		case 256:
+			signingKey.Method = jwt.SigningMethodES256
+		case 384:
+			signingKey.Method = jwt.SigningMethodES384
+		case 521:
+			signingKey.Method = jwt.SigningMethodES512
+		default:
+			return nil, fmt.Errorf("unsupported ecdsa curve in %s", path)
+		}
+		signingKey.PrivateKey = privateKey
+	case ed25519.PrivateKey:
+		signingKey.Method = jwt.SigningMethodEdDSA
+		signingKey.PrivateKey = privateKey
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T in %s", privateKey, path)
+	}
+
+	return signingKey, nil
+}
+
+func (keySet *KeySet) Sign(claims jwt.Claims) (string, error) {
+	signingKey, ok := keySet.Keys[keySet.SigningKid]
+	if !ok {
+		if len(keySet.HmacKey) == 0 {
+			return "", errors.New("no id token signing key configured")
+		}
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(keySet.HmacKey)
+	}
+
+	token := jwt.NewWithClaims(signingKey.Method, claims)
+	token.Header["kid"] = signingKey.Kid
+	return token.SignedString(signingKey.PrivateKey)
+}
+
+// Keyfunc pick the verification key by the kid header and make sure the token algorithm match the key
+func (keySet *KeySet) Keyfunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		if len(keySet.HmacKey) == 0 || token.Method != jwt.SigningMethodHS256 {
+			return nil, errors.New("missing kid header")
+		}
+		return keySet.HmacKey, nil
+	}
+
+	signingKey, ok := keySet.Keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if token.Method.Alg() != signingKey.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %q for key id %q", token.Method.Alg(), kid)
+	}
+
+	return signingKey.PrivateKey.Public(), nil
+}
+
+// JWKS return the public part of every asymmetric key, the legacy hmac key is never published
+func (keySet *KeySet) JWKS() (oidc.JSONWebKeySet, error) {
+	jwks := oidc.JSONWebKeySet{Keys: []oidc.JSONWebKey{}}
+	for _, signingKey := range keySet.Keys {
+		jwk, err := oidc.NewJSONWebKey(signingKey.Kid, signingKey.Method.Alg(), signingKey.PrivateKey.Public())
+		if err != nil {
+			return jwks, err
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	slices.SortFunc(jwks.Keys, func(a, b oidc.JSONWebKey) int {
+		return strings.Compare(a.Kid, b.Kid)
+	})
+	return jwks, nil
+}
@@ -53,6 +53,20 @@ func (r *Cache) GetBytes(ctx context.Context, key string) (data []byte, err erro
 	return data, nil
 }
 
+// GetDelBytes get the data and delete the key atomically, used for values that may only be consumed once
+func (r *Cache) GetDelBytes(ctx context.Context, key string) (data []byte, err error) {
+	data, err = r.Client.GetDel(ctx, key).Bytes()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		logger.LogError(ctx, "error cache.GetDelBytes", []zap.Field{
+			zap.Error(err),
+			zap.Strings("tags", []string{"cache", "GetDelBytes"}),
+		}...)
+		return []byte{}, err
+	}
+
+	return data, nil
+}
+
 func (r *Cache) GetWithTtl(ctx context.Context, key string) (string, time.Duration, error) {
 	pipe := r.Client.Pipeline()
 
@@ -116,6 +130,20 @@ func (r *Cache) Set(ctx context.Context, key string, data any, expiration time.D
 	return nil
 }
 
+// SetNX set the data only when the key doesn't exist yet, isSet is false when the key already exist
+func (r *Cache) SetNX(ctx context.Context, key string, data any, expiration time.Duration) (isSet bool, err error) {
+	isSet, err = r.Client.SetNX(ctx, key, data, expiration).Result()
+	if err != nil {
+		logger.LogError(ctx, "error cache.SetNX", []zap.Field{
+			zap.Error(err),
+			zap.Strings("tags", []string{"cache", "SetNX"}),
+		}...)
+		return false, err
+	}
+
+	return isSet, nil
+}
+
 func (r *Cache) Del(ctx context.Context, keys ...string) (err error) {
 	err = r.Client.Del(ctx, keys...).Err()
 	if err != nil {
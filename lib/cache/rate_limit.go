@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"app/lib/logger"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration
+}
+
+// slidingWindowScript keeps one sorted set member per request scored by its timestamp in milliseconds,
+// members older than the window are dropped before counting so the limit applies to any rolling window
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+local member = ARGV[4]
+
+redis.call("ZREMRANGEBYSCORE", key, "-inf", now - window)
+local count = redis.call("ZCARD", key)
+local allowed = 0
+if count < limit then
+	redis.call("ZADD", key, now, member)
+	count = count + 1
+	allowed = 1
+end
+redis.call("PEXPIRE", key, window)
+
+local resetAfter = window
+local oldest = redis.call("ZRANGE", key, 0, 0, "WITHSCORES")
+if oldest[2] then
+	resetAfter = tonumber(oldest[2]) + window - now
+end
+
+return {allowed, count, resetAfter}
+`)
+
+// SlidingWindow register a hit on key and report whether it is still within limit hits per window
+func (r *Cache) SlidingWindow(ctx context.Context, key string, limit int, window time.Duration) (res RateLimitResult, err error) {
+	now := time.Now().UnixMilli()
+	member := fmt.Sprintf("%d-%s", now, uuid.NewString())
+
+	values, err := slidingWindowScript.Run(ctx, r.Client, []string{key}, now, window.Milliseconds(), limit, member).Int64Slice()
+	if err != nil {
+		logger.LogError(ctx, "error cache.SlidingWindow", []zap.Field{
+			zap.Error(err),
+			zap.Strings("tags", []string{"cache", "SlidingWindow"}),
+		}...)
+		return res, err
+	}
+
+	res.Allowed = values[0] == 1
+	res.Limit = limit
+	res.Remaining = max(limit-int(values[1]), 0)
+	res.ResetAfter = time.Duration(values[2]) * time.Millisecond
+	return res, nil
+}
@@ -0,0 +1,11 @@
+package constant
+
+const (
+	AppSettingPasswordMinLength        = "password_min_length"
+	AppSettingPasswordRequireUppercase = "password_require_uppercase"
+	AppSettingPasswordRequireLowercase = "password_require_lowercase"
+	AppSettingPasswordRequireNumber    = "password_require_number"
+	AppSettingPasswordRequireSpecial   = "password_require_special"
+	AppSettingPasswordHistoryDepth     = "password_history_depth"
+	AppSettingPasswordMaxAge           = "password_max_age" // In days, 0 to disable
+)
@@ -5,13 +5,37 @@ const (
 	MfaFlagKeyPrefix               = "mfa-flag:%d"                   // mfa-flag:[identifier]
 	AccessTokenKeyPrefix           = "access-token:%s"               // access-token:[access_token]
 	OtpTokenKeyPrefix              = "otp-token:%s"                  // otp-token:[otp_token]
-	SendOtpCtrKeyPrefix            = "send-otp-ctr:%d:%s"            // send-otp-ctr:[identifier]:[otp_type]
-	SendOtpDelayKeyPrefix          = "send-otp-delay:%d:%s"          // send-otp-delay:[identifier]:[otp_type]
+	SendOtpCtrKeyPrefix            = "send-otp-ctr:%d:%s:%s"         // send-otp-ctr:[identifier]:[otp_type]:[otp_channel]
+	SendOtpDelayKeyPrefix          = "send-otp-delay:%d:%s:%s"       // send-otp-delay:[identifier]:[otp_type]:[otp_channel]
+	LoginFailedCtrKeyPrefix        = "login-failed-ctr:%s:%s"        // login-failed-ctr:[lockout_scope]:[identifier]
+	LoginLockKeyPrefix             = "login-lock:%s:%s"              // login-lock:[lockout_scope]:[identifier]
+	LoginLockLevelKeyPrefix        = "login-lock-level:%s:%s"        // login-lock-level:[lockout_scope]:[identifier]
+	RateLimitKeyPrefix             = "rate-limit:%s:%s"              // rate-limit:[limiter_name]:[identifier]
+	WebauthnSessionKeyPrefix       = "webauthn-session:%s:%s"        // webauthn-session:[ceremony]:[session_id]
+	SessionTouchedKeyPrefix        = "session-touched:%s"            // session-touched:[access_token]
+
+	SessionLastUsedInterval = 60 // In seconds, minimum interval between session last_used_at updates
 
 	DefaultIssuer   = "DefaultIssuer"
 	DefaultAudience = "DefaultAudience"
 
 	OtpTypeLogin = "LOGIN"
 
-	OtpChannelEmail = "EMAIL"
+	LockoutScopeAccount = "account"
+	LockoutScopeIP      = "ip"
+	LockoutScopeMfa     = "mfa"
+
+	TotpAppPeriod     = 30  // In seconds, most authenticator apps only support 30 seconds period
+	TotpQrCodePngSize = 256 // In pixels
+
+	RecoveryCodeCount  = 10
+	RecoveryCodeLength = 10 // Without separator
+
+	OtpChannelEmail    = "EMAIL"
+	OtpChannelSms      = "SMS"
+	OtpChannelWhatsapp = "WHATSAPP"
+
+	WebauthnCeremonyRegistration = "registration"
+	WebauthnCeremonyLogin        = "login"
+	WebauthnCeremonyMfa          = "mfa"
 )
@@ -1,5 +1,12 @@
 package constant
 
+const (
+	UploadPathAvatar           = "avatar"
+	UploadPathUserImport       = "user_import"
+	UploadPathUserImportReport = "user_import_report"
+	UploadPathUserExport       = "user_export"
+)
+
 type UploadFileProps struct {
 	// FormValue is request from form which contain file.
 	FormValue string
@@ -17,4 +24,14 @@ var MapUploadFileProps = map[string]UploadFileProps{
 		AllowedExtensions: []string{"jpg", "jpeg", "png", "webp"},
 		MaxFilesize:       2,
 	},
+	UploadPathAvatar: {
+		FormValue:         "file",
+		AllowedExtensions: []string{"jpg", "jpeg", "png", "webp"},
+		MaxFilesize:       2,
+	},
+	UploadPathUserImport: {
+		FormValue:         "file",
+		AllowedExtensions: []string{"csv", "xlsx"},
+		MaxFilesize:       10,
+	},
 }
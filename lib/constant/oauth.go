@@ -0,0 +1,20 @@
+package constant
+
+import "regexp"
+
+const (
+	GrantTypeClientCredentials = "client_credentials"
+	TokenTypeBearer            = "Bearer"
+
+	ApiClientIDPrefix     = "client_"
+	ApiClientSecretLength = 32 // In bytes, before encoding
+
+	ApiKeyPrefix        = "sk_"
+	ApiKeyLength        = 32 // In bytes, before encoding
+	ApiKeyDisplayLength = 8  // Number of characters kept in key_prefix so the user can recognize the key
+	ApiKeyHeader        = "X-API-Key"
+	ApiKeyAuthScheme    = "ApiKey"
+)
+
+// ScopeRegex follow the permission slug format, scopes are checked against the same names as permissions
+var ScopeRegex = regexp.MustCompile(`^[a-z_]+:[a-z_]+$`)
@@ -0,0 +1,15 @@
+package constant
+
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+
+	PermissionUsersRead   = "users:read"
+	PermissionUsersCreate = "users:create"
+	PermissionUsersUpdate = "users:update"
+	PermissionUsersDelete = "users:delete"
+
+	PermissionApiClientsManage = "api_clients:manage"
+
+	PermissionAuditLogsRead = "audit_logs:read"
+)
@@ -2,5 +2,15 @@ package constant
 
 const (
 	TaskTypeEmailSend                 = "email:send"
+	TaskTypeSmsSend                   = "sms:send"
 	TaskTypeWebsocketBroadcastMessage = "websocket:broadcast-message"
+	TaskTypeUserErase                 = "user:erase"
+	TaskTypeUserImport                = "user:import"
+	TaskTypeUserExport                = "user:export"
 )
+
+// JobTaskTypes are the task types published with PublishJob, the worker only keep the job status of these tasks
+var JobTaskTypes = []string{
+	TaskTypeUserImport,
+	TaskTypeUserExport,
+}
@@ -95,4 +95,58 @@ var (
 		CodeString: "ERROR_OTP_INVALID",
 		HTTPCode:   http.StatusUnprocessableEntity,
 	}
+	ErrorForbidden = CustomError{
+		Message:    "Error Forbidden",
+		Code:       1012,
+		CodeString: "ERROR_FORBIDDEN",
+		HTTPCode:   http.StatusForbidden,
+	}
+	ErrorAccountLocked = CustomError{
+		Message:    "Error Account Locked",
+		Code:       1013,
+		CodeString: "ERROR_ACCOUNT_LOCKED",
+		HTTPCode:   http.StatusTooManyRequests,
+	}
+	ErrorRateLimit = CustomError{
+		Message:    "Error Rate Limit Exceeded",
+		Code:       1014,
+		CodeString: "ERROR_RATE_LIMIT",
+		HTTPCode:   http.StatusTooManyRequests,
+	}
+	ErrorWebauthnInvalid = CustomError{
+		Message:    "Error Webauthn Invalid",
+		Code:       1015,
+		CodeString: "ERROR_WEBAUTHN_INVALID",
+		HTTPCode:   http.StatusUnprocessableEntity,
+	}
+	ErrorVerificationExpired = CustomError{
+		Message:    "Error Verification Expired",
+		Code:       1016,
+		CodeString: "ERROR_VERIFICATION_EXPIRED",
+		HTTPCode:   http.StatusBadRequest,
+	}
+	ErrorPasswordExpired = CustomError{
+		Message:    "Error Password Expired",
+		Code:       1017,
+		CodeString: "ERROR_PASSWORD_EXPIRED",
+		HTTPCode:   http.StatusForbidden,
+	}
+	ErrorAccountInactive = CustomError{
+		Message:    "Error Account Inactive",
+		Code:       1018,
+		CodeString: "ERROR_ACCOUNT_INACTIVE",
+		HTTPCode:   http.StatusForbidden,
+	}
+	ErrorMfaNotEnrolled = CustomError{
+		Message:    "Error Mfa Not Enrolled",
+		Code:       1019,
+		CodeString: "ERROR_MFA_NOT_ENROLLED",
+		HTTPCode:   http.StatusBadRequest,
+	}
+	ErrorWebauthnCloned = CustomError{
+		Message:    "Error Webauthn Cloned",
+		Code:       1020,
+		CodeString: "ERROR_WEBAUTHN_CLONED",
+		HTTPCode:   http.StatusForbidden,
+	}
 )
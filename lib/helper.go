@@ -5,8 +5,8 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-func GeneratePasswordHash(str string) (string, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(str), bcrypt.MinCost)
+func GeneratePasswordHash(str string, cost int) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(str), cost)
 	if err != nil {
 		return "", err
 	}
@@ -21,6 +21,15 @@ func CompareHashAndPassword(hashedPassword string, password string) error {
 	return nil
 }
 
+// IsPasswordHashCostLower check whether the hash was generated with a lower cost than the given one
+func IsPasswordHashCostLower(hashedPassword string, cost int) bool {
+	hashCost, err := bcrypt.Cost([]byte(hashedPassword))
+	if err != nil {
+		return false
+	}
+	return hashCost < cost
+}
+
 func GenerateUUID() string {
 	return uuid.NewString()
 }
@@ -0,0 +1,206 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	ProviderGoogle    = "google"
+	ProviderMicrosoft = "microsoft"
+
+	// jwksMinRefreshInterval prevent tokens with unknown kid from hammering the provider jwks endpoint
+	jwksMinRefreshInterval = time.Minute
+)
+
+type Providers map[string]*Provider
+
+type Provider struct {
+	Name         string
+	Issuer       string
+	Audiences    []string
+	Client       *http.Client
+	JwksCacheTtl time.Duration
+
+	mu             sync.RWMutex
+	discovery      *discovery
+	keys           map[string]any
+	keysFetchedAt  time.Time
+	keysRefreshing sync.Mutex
+}
+
+type Claims struct {
+	Email         string `json:"email"`
+	EmailVerified any    `json:"email_verified"` // Some providers send it as string
+	Name          string `json:"name"`
+	TenantID      string `json:"tid"`
+	jwt.RegisteredClaims
+}
+
+func (c *Claims) IsEmailVerified() bool {
+	switch emailVerified := c.EmailVerified.(type) {
+	case bool:
+		return emailVerified
+	case string:
+		return emailVerified == "true"
+	default:
+		return false
+	}
+}
+
+type discovery struct {
+	Issuer  string `json:"issuer"`
+	JwksURI string `json:"jwks_uri"`
+}
+
+// Verify validate the id token signature against the provider jwks, the issuer and the audience,
+// the discovery document and the keys are fetched lazily and cached
+func (p *Provider) Verify(ctx context.Context, rawIDToken string) (*Claims, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &Claims{}
+	_, err = jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.getKey(ctx, discovery.JwksURI, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+		jwt.WithAudience(p.Audiences...),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Multi tenant issuers (e.g. microsoft common endpoint) use a {tenantid} template in the discovery document
+	expectedIssuer := strings.ReplaceAll(discovery.Issuer, "{tenantid}", claims.TenantID)
+	if claims.Issuer != expectedIssuer {
+		return nil, fmt.Errorf("unexpected issuer %q", claims.Issuer)
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("missing subject")
+	}
+
+	return claims, nil
+}
+
+func (p *Provider) getDiscovery(ctx context.Context) (*discovery, error) {
+	p.mu.RLock()
+	cachedDiscovery := p.discovery
+	p.mu.RUnlock()
+	if cachedDiscovery != nil {
+		return cachedDiscovery, nil
+	}
+
+	discoveryURL := strings.TrimSuffix(p.Issuer, "/") + "/.well-known/openid-configuration"
+	res := &discovery{}
+	err := p.getJson(ctx, discoveryURL, res)
+	if err != nil {
+		return nil, err
+	}
+	if res.JwksURI == "" {
+		return nil, errors.New("discovery document does not contain jwks_uri")
+	}
+
+	p.mu.Lock()
+	p.discovery = res
+	p.mu.Unlock()
+	return res, nil
+}
+
+func (p *Provider) getKey(ctx context.Context, jwksURI, kid string) (any, error) {
+	p.mu.RLock()
+	key, ok := p.keys[kid]
+	isExpired := time.Since(p.keysFetchedAt) > p.JwksCacheTtl
+	canRefresh := time.Since(p.keysFetchedAt) > jwksMinRefreshInterval
+	p.mu.RUnlock()
+	if ok && !isExpired {
+		return key, nil
+	}
+	if !ok && !isExpired && !canRefresh {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	err := p.refreshKeys(ctx, jwksURI)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.RLock()
+	key, ok = p.keys[kid]
+	p.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	return key, nil
+}
+
+func (p *Provider) refreshKeys(ctx context.Context, jwksURI string) error {
+	p.keysRefreshing.Lock()
+	defer p.keysRefreshing.Unlock()
+
+	// Another request may have refreshed the keys while this one was waiting
+	p.mu.RLock()
+	isFresh := time.Since(p.keysFetchedAt) < jwksMinRefreshInterval
+	p.mu.RUnlock()
+	if isFresh {
+		return nil
+	}
+
+	jwks := JSONWebKeySet{}
+	err := p.getJson(ctx, jwksURI, &jwks)
+	if err != nil {
+		return err
+	}
+
+	keys := map[string]any{}
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		publicKey, err := jwk.PublicKey()
+		if err != nil {
+			// Skip key types we do not support instead of failing the whole set
+			continue
+		}
+		keys[jwk.Kid] = publicKey
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.keysFetchedAt = time.Now()
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *Provider) getJson(ctx context.Context, url string, dst any) error {
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	httpResponse, err := p.Client.Do(httpRequest)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+
+	if httpResponse.StatusCode != http.StatusOK {
+		return fmt.Errorf("get %s responded with status %d", url, httpResponse.StatusCode)
+	}
+
+	return json.NewDecoder(httpResponse.Body).Decode(dst)
+}
@@ -0,0 +1,57 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const rangePrefixLength = 5
+
+// IsBreached look up the password in a local copy of the breached password list stored in k-anonymity format,
+// the directory contains one file per 5 character sha1 prefix and every line of the file is SUFFIX:COUNT.
+// A missing range file is treated as not breached.
+func IsBreached(dir string, password string) (bool, error) {
+	if dir == "" {
+		return false, nil
+	}
+
+	hash := sha1.Sum([]byte(password))
+	hashHex := strings.ToUpper(hex.EncodeToString(hash[:]))
+	prefix, suffix := hashHex[:rangePrefixLength], hashHex[rangePrefixLength:]
+
+	file, err := openRangeFile(dir, prefix)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		lineSuffix, count, _ := strings.Cut(line, ":")
+		if !strings.EqualFold(lineSuffix, suffix) {
+			continue
+		}
+		// Padding entries are published with a zero count and are not real breaches
+		return strings.TrimSpace(count) != "0", nil
+	}
+
+	return false, scanner.Err()
+}
+
+// openRangeFile open the range file of the prefix, both PREFIX and PREFIX.txt names are accepted
+func openRangeFile(dir string, prefix string) (*os.File, error) {
+	file, err := os.Open(filepath.Join(dir, prefix))
+	if err == nil || !errors.Is(err, os.ErrNotExist) {
+		return file, err
+	}
+	return os.Open(filepath.Join(dir, prefix+".txt"))
+}
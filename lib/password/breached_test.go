@@ -0,0 +1,60 @@
+package password
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIsBreached(t *testing.T) {
+	prefix, suffix := hashRange("password")
+	zeroPrefix, zeroSuffix := hashRange("padding")
+	txtPrefix, txtSuffix := hashRange("letmein")
+
+	dir := t.TempDir()
+	writeRangeFile(t, filepath.Join(dir, prefix), "0000000000000000000000000000000000A:3\n"+suffix+":52256179\n")
+	writeRangeFile(t, filepath.Join(dir, zeroPrefix), zeroSuffix+":0\n")
+	writeRangeFile(t, filepath.Join(dir, txtPrefix+".txt"), strings.ToLower(txtSuffix)+":12\r\n")
+
+	tests := []struct {
+		name     string
+		dir      string
+		password string
+		want     bool
+	}{
+		{name: "disabled", dir: "", password: "password", want: false},
+		{name: "breached", dir: dir, password: "password", want: true},
+		{name: "padding entry", dir: dir, password: "padding", want: false},
+		{name: "txt range file and lower case suffix", dir: dir, password: "letmein", want: true},
+		{name: "missing range file", dir: dir, password: "correct horse battery staple", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IsBreached(tt.dir, tt.password)
+			if err != nil {
+				t.Fatalf("err = %v, want nil", err)
+			}
+			if got != tt.want {
+				t.Errorf("breached = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func hashRange(password string) (string, string) {
+	hash := sha1.Sum([]byte(password))
+	hashHex := strings.ToUpper(hex.EncodeToString(hash[:]))
+	return hashHex[:rangePrefixLength], hashHex[rangePrefixLength:]
+}
+
+func writeRangeFile(t *testing.T, path string, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
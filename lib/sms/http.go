@@ -0,0 +1,50 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// HTTP send message through a generic http provider, the payload is posted as json to the configured url
+type HTTP struct {
+	Client   *http.Client
+	URL      string
+	APIKey   string
+	SenderID string
+}
+
+func (s *HTTP) SendMessage(ctx context.Context, param SendMessageParam) error {
+	body, err := json.Marshal(map[string]any{
+		"from":    s.SenderID,
+		"to":      param.To,
+		"channel": param.Channel,
+		"message": param.Message,
+	})
+	if err != nil {
+		return err
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.APIKey))
+
+	httpResponse, err := s.Client.Do(httpRequest)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+
+	if httpResponse.StatusCode < 200 || httpResponse.StatusCode >= 300 {
+		responseBody, _ := io.ReadAll(io.LimitReader(httpResponse.Body, 1024))
+		return fmt.Errorf("sms provider responded with status %d: %s", httpResponse.StatusCode, string(responseBody))
+	}
+
+	return nil
+}
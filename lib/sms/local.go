@@ -0,0 +1,32 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Local write every message to a file instead of sending it, intended for development
+type Local struct {
+	Directory string
+}
+
+func (s *Local) SendMessage(ctx context.Context, param SendMessageParam) error {
+	err := os.MkdirAll(s.Directory, 0700)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(filepath.Join(s.Directory, "sms.log"), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		log.Println("failed open local sms file: ", err)
+		return err
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "%s [%s] to=%s message=%q\n", time.Now().Format(time.RFC3339), param.Channel, param.To, param.Message)
+	return err
+}
@@ -0,0 +1,13 @@
+package sms
+
+import "context"
+
+type Sender interface {
+	SendMessage(ctx context.Context, param SendMessageParam) error
+}
+
+type SendMessageParam struct {
+	To      string `json:"to"`
+	Channel string `json:"channel"`
+	Message string `json:"message"`
+}
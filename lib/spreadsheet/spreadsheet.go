@@ -0,0 +1,127 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	FormatCsv  = "csv"
+	FormatXlsx = "xlsx"
+)
+
+// ReadRows read every row of a csv file or of the first sheet of a xlsx file
+func ReadRows(format string, reader io.Reader) ([][]string, error) {
+	switch format {
+	case FormatCsv:
+		csvReader := csv.NewReader(reader)
+		csvReader.FieldsPerRecord = -1
+		csvReader.TrimLeadingSpace = true
+		return csvReader.ReadAll()
+	case FormatXlsx:
+		file, err := excelize.OpenReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		sheets := file.GetSheetList()
+		if len(sheets) == 0 {
+			return [][]string{}, nil
+		}
+		return file.GetRows(sheets[0])
+	}
+
+	return nil, fmt.Errorf("unsupported spreadsheet format: %s", format)
+}
+
+// EscapeFormula prefix the value with a quote when it starts with a character a spreadsheet application would
+// evaluate as a formula, so user controlled values can't run formulas when the exported file is opened
+func EscapeFormula(value string) string {
+	if value != "" && strings.ContainsAny(value[:1], "=+-@\t\r") {
+		return "'" + value
+	}
+	return value
+}
+
+// Writer write rows one by one into a csv or xlsx file, Close must be called to flush the rows into the file
+type Writer interface {
+	Write(row []string) error
+	Close() error
+}
+
+func NewWriter(format string, path string) (Writer, error) {
+	switch format {
+	case FormatCsv:
+		file, err := os.Create(path)
+		if err != nil {
+			return nil, err
+		}
+		return &csvWriter{file: file, writer: csv.NewWriter(file)}, nil
+	case FormatXlsx:
+		file := excelize.NewFile()
+		streamWriter, err := file.NewStreamWriter(file.GetSheetName(0))
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		return &xlsxWriter{path: path, file: file, streamWriter: streamWriter}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported spreadsheet format: %s", format)
+}
+
+type csvWriter struct {
+	file   *os.File
+	writer *csv.Writer
+}
+
+func (w *csvWriter) Write(row []string) error {
+	return w.writer.Write(row)
+}
+
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	err := w.writer.Error()
+	if err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+type xlsxWriter struct {
+	path         string
+	file         *excelize.File
+	streamWriter *excelize.StreamWriter
+	rowNumber    int
+}
+
+func (w *xlsxWriter) Write(row []string) error {
+	w.rowNumber++
+	cell, err := excelize.CoordinatesToCellName(1, w.rowNumber)
+	if err != nil {
+		return err
+	}
+
+	values := make([]any, len(row))
+	for i, value := range row {
+		values[i] = value
+	}
+	return w.streamWriter.SetRow(cell, values)
+}
+
+func (w *xlsxWriter) Close() error {
+	defer w.file.Close()
+
+	err := w.streamWriter.Flush()
+	if err != nil {
+		return err
+	}
+	return w.file.SaveAs(w.path)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"log"
+	"slices"
 	"strings"
 
 	"github.com/coder/websocket"
@@ -86,3 +87,22 @@ func (c *Client) readPump() {
 		// Write parse and broadcast message from frontend to server connection here...
 	}
 }
+
+// isRecipient check whether the message targeted to this client, untargeted message is delivered to all clients
+func (c *Client) isRecipient(message Message) bool {
+	if len(message.UserIDs) == 0 && len(message.Roles) == 0 {
+		return true
+	}
+
+	if c.userId > 0 && slices.Contains(message.UserIDs, c.userId) {
+		return true
+	}
+
+	for _, role := range c.userRoles {
+		if slices.Contains(message.Roles, role) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -58,7 +58,10 @@ func (h *Hub) Run() {
 		case message := <-h.broadcast:
 			h.mu.RLock()
 			for client := range h.clients {
-				// TODO: validate by user id & roles
+				if !client.isRecipient(message) {
+					continue
+				}
+
 				select {
 				case client.send <- message:
 				default:
@@ -1,15 +1,22 @@
 package websocket
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 var (
 	MessageTypeNotification = "NOTIFICATION"
+	MessageTypeJob          = "JOB"
 )
 
-// Message represents a message
+// Message represents a message, when UserIDs or Roles is set the message only delivered to the matching clients
 type Message struct {
 	MessageType  string        `json:"message_type"`
 	Notification *Notification `json:"notification"`
+	Job          *Job          `json:"job,omitempty"`
+	UserIDs      []uint        `json:"user_ids,omitempty"`
+	Roles        []string      `json:"roles,omitempty"`
 	Timestamp    time.Time     `json:"timestamp"`
 }
 
@@ -19,3 +26,13 @@ type Notification struct {
 	Title            string `json:"title"`
 	Message          string `json:"message"`
 }
+
+// Job represents a status update of a job, it is only delivered to the owner of the job
+type Job struct {
+	ID       string          `json:"id"`
+	Type     string          `json:"type"`
+	State    string          `json:"state"`
+	Progress int             `json:"progress"`
+	Result   json.RawMessage `json:"result,omitempty"`
+	Error    string          `json:"error"`
+}
@@ -45,13 +45,14 @@ func (ws *Websocket) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 
 	// Create client
 	client := &Client{
-		conn:   conn,
-		send:   make(chan Message, 256),
-		hub:    ws.Hub,
-		id:     fmt.Sprintf("client-%d-%s", time.Now().UnixNano(), idTokenClaims.Subject),
-		userId: idTokenClaims.UserID,
-		ctx:    ctx,
-		cancel: cancel,
+		conn:      conn,
+		send:      make(chan Message, 256),
+		hub:       ws.Hub,
+		id:        fmt.Sprintf("client-%d-%s", time.Now().UnixNano(), idTokenClaims.Subject),
+		userId:    idTokenClaims.UserID,
+		userRoles: idTokenClaims.Roles,
+		ctx:       ctx,
+		cancel:    cancel,
 	}
 
 	// Register client
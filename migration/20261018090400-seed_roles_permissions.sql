@@ -0,0 +1,22 @@
+-- +migrate Up
+INSERT INTO roles (name, slug, created_at, updated_at) VALUES
+    ('Administrator', 'admin', NOW(), NOW()),
+    ('User', 'user', NOW(), NOW())
+ON CONFLICT (slug) DO NOTHING;
+
+INSERT INTO permissions (name, slug, created_at, updated_at) VALUES
+    ('Read Users', 'users:read', NOW(), NOW()),
+    ('Create Users', 'users:create', NOW(), NOW()),
+    ('Update Users', 'users:update', NOW(), NOW()),
+    ('Delete Users', 'users:delete', NOW(), NOW())
+ON CONFLICT (slug) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id FROM roles, permissions
+WHERE roles.slug = 'admin' AND permissions.slug IN ('users:read', 'users:create', 'users:update', 'users:delete')
+ON CONFLICT DO NOTHING;
+
+-- +migrate Down
+DELETE FROM role_permissions WHERE role_id IN (SELECT id FROM roles WHERE slug IN ('admin', 'user'));
+DELETE FROM permissions WHERE slug IN ('users:read', 'users:create', 'users:update', 'users:delete');
+DELETE FROM roles WHERE slug IN ('admin', 'user');
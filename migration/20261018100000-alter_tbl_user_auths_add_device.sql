@@ -0,0 +1,11 @@
+-- +migrate Up
+ALTER TABLE user_auths
+    ADD COLUMN IF NOT EXISTS user_agent TEXT,
+    ADD COLUMN IF NOT EXISTS ip_address VARCHAR(45),
+    ADD COLUMN IF NOT EXISTS last_used_at TIMESTAMPTZ;
+
+-- +migrate Down
+ALTER TABLE user_auths
+    DROP COLUMN IF EXISTS user_agent,
+    DROP COLUMN IF EXISTS ip_address,
+    DROP COLUMN IF EXISTS last_used_at;
@@ -0,0 +1,17 @@
+-- +migrate Up
+ALTER TABLE user_auths
+    ADD COLUMN IF NOT EXISTS family_id VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS rotated_at TIMESTAMPTZ;
+
+UPDATE user_auths SET family_id = md5(id::text || random()::text) WHERE family_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_user_auths_family_id ON user_auths (family_id);
+CREATE INDEX IF NOT EXISTS idx_user_auths_refresh_token ON user_auths (refresh_token);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_user_auths_refresh_token;
+DROP INDEX IF EXISTS idx_user_auths_family_id;
+
+ALTER TABLE user_auths
+    DROP COLUMN IF EXISTS family_id,
+    DROP COLUMN IF EXISTS rotated_at;
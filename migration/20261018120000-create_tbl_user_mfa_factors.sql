@@ -0,0 +1,16 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS user_mfa_factors (
+    id SERIAL NOT NULL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    type VARCHAR(255) NOT NULL,
+    secret TEXT NOT NULL,
+    confirmed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_mfa_factors_user_id ON user_mfa_factors (user_id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS user_mfa_factors;
@@ -0,0 +1,28 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS api_clients (
+    id SERIAL NOT NULL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    client_id VARCHAR(255) NOT NULL,
+    client_secret_hash TEXT NOT NULL,
+    scopes TEXT NOT NULL DEFAULT '',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_clients_client_id ON api_clients (client_id);
+
+INSERT INTO permissions (name, slug, created_at, updated_at) VALUES
+    ('Manage API Clients', 'api_clients:manage', NOW(), NOW())
+ON CONFLICT (slug) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id FROM roles, permissions
+WHERE roles.slug = 'admin' AND permissions.slug = 'api_clients:manage'
+ON CONFLICT DO NOTHING;
+
+-- +migrate Down
+DELETE FROM role_permissions WHERE permission_id IN (SELECT id FROM permissions WHERE slug = 'api_clients:manage');
+DELETE FROM permissions WHERE slug = 'api_clients:manage';
+DROP TABLE IF EXISTS api_clients;
@@ -0,0 +1,9 @@
+-- +migrate Up
+UPDATE user_verifications SET expired_at = created_at + INTERVAL '1 day' WHERE expired_at IS NULL AND type = 'VERIFY_ACCOUNT';
+UPDATE user_verifications SET expired_at = created_at + INTERVAL '1 hour' WHERE expired_at IS NULL AND type = 'RESET_PASSWORD';
+UPDATE user_verifications SET expired_at = created_at + INTERVAL '15 minutes' WHERE expired_at IS NULL AND type = 'MAGIC_LOGIN';
+
+CREATE INDEX IF NOT EXISTS idx_user_verifications_expired_at ON user_verifications (expired_at);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_user_verifications_expired_at;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE user_verifications ADD COLUMN IF NOT EXISTS target VARCHAR(255) NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE user_verifications DROP COLUMN IF EXISTS target;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMPTZ;
+
+UPDATE users SET password_changed_at = updated_at WHERE password_changed_at IS NULL AND encrypted_password <> '';
+
+-- +migrate Down
+ALTER TABLE users DROP COLUMN IF EXISTS password_changed_at;
@@ -0,0 +1,24 @@
+-- +migrate Up
+INSERT INTO app_settings (name, value, slug, created_at, updated_at)
+SELECT settings.name, settings.value, settings.slug, NOW(), NOW()
+FROM (VALUES
+    ('Password Minimum Length', '8', 'password_min_length'),
+    ('Password Require Uppercase', 'false', 'password_require_uppercase'),
+    ('Password Require Lowercase', 'true', 'password_require_lowercase'),
+    ('Password Require Number', 'true', 'password_require_number'),
+    ('Password Require Special Character', 'true', 'password_require_special'),
+    ('Password History Depth', '5', 'password_history_depth'),
+    ('Password Max Age (Days)', '0', 'password_max_age')
+) AS settings (name, value, slug)
+WHERE NOT EXISTS (SELECT 1 FROM app_settings WHERE app_settings.slug = settings.slug);
+
+-- +migrate Down
+DELETE FROM app_settings WHERE slug IN (
+    'password_min_length',
+    'password_require_uppercase',
+    'password_require_lowercase',
+    'password_require_number',
+    'password_require_special',
+    'password_history_depth',
+    'password_max_age'
+);
@@ -0,0 +1,19 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS jobs (
+    id VARCHAR(64) NOT NULL PRIMARY KEY,
+    type VARCHAR(255) NOT NULL,
+    user_id INTEGER NOT NULL DEFAULT 0,
+    state VARCHAR(32) NOT NULL,
+    progress INTEGER NOT NULL DEFAULT 0,
+    result TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_user_id ON jobs (user_id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS jobs;
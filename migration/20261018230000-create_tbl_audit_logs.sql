@@ -0,0 +1,31 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id SERIAL NOT NULL PRIMARY KEY,
+    actor_id INTEGER NOT NULL DEFAULT 0,
+    action VARCHAR(64) NOT NULL,
+    target_type VARCHAR(64) NOT NULL DEFAULT '',
+    target_id INTEGER NOT NULL DEFAULT 0,
+    changes TEXT NOT NULL DEFAULT '',
+    ip_address VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    request_id VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON audit_logs (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_target ON audit_logs (target_type, target_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs (created_at);
+
+INSERT INTO permissions (name, slug, created_at, updated_at) VALUES
+    ('Read Audit Logs', 'audit_logs:read', NOW(), NOW())
+ON CONFLICT (slug) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id FROM roles, permissions
+WHERE roles.slug = 'admin' AND permissions.slug = 'audit_logs:read'
+ON CONFLICT DO NOTHING;
+
+-- +migrate Down
+DELETE FROM role_permissions WHERE permission_id IN (SELECT id FROM permissions WHERE slug = 'audit_logs:read');
+DELETE FROM permissions WHERE slug = 'audit_logs:read';
+DROP TABLE IF EXISTS audit_logs;
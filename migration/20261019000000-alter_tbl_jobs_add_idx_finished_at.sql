@@ -0,0 +1,5 @@
+-- +migrate Up
+CREATE INDEX IF NOT EXISTS idx_jobs_finished_at ON jobs (finished_at);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_jobs_finished_at;
@@ -0,0 +1,7 @@
+-- +migrate Up
+INSERT INTO user_roles (user_id, role_id)
+SELECT users.id, roles.id FROM users, roles
+WHERE roles.slug = 'user'
+ON CONFLICT DO NOTHING;
+
+-- +migrate Down
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ApiClient struct {
+	ID               uint           `json:"id" gorm:"primaryKey"`
+	Name             string         `json:"name"`
+	ClientID         string         `json:"client_id"`
+	ClientSecretHash string         `json:"-"`
+	Scopes           string         `json:"scopes"` // Space separated, as in the oauth2 scope parameter
+	IsActive         bool           `json:"is_active"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `json:"deleted_at"`
+}
+
+func (apiClient ApiClient) GetScopes() []string {
+	return strings.Fields(apiClient.Scopes)
+}
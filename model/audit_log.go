@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+const (
+	AuditLogActionUserCreate             = "USER_CREATE"
+	AuditLogActionUserUpdate             = "USER_UPDATE"
+	AuditLogActionUserDelete             = "USER_DELETE"
+	AuditLogActionUserDeactivate         = "USER_DEACTIVATE"
+	AuditLogActionUserReactivate         = "USER_REACTIVATE"
+	AuditLogActionPasswordReset          = "PASSWORD_RESET"
+	AuditLogActionPasswordChange         = "PASSWORD_CHANGE"
+	AuditLogActionLogin                  = "LOGIN"
+	AuditLogActionLoginFailed            = "LOGIN_FAILED"
+	AuditLogActionMfaValidate            = "MFA_VALIDATE"
+	AuditLogActionMfaValidateFailed      = "MFA_VALIDATE_FAILED"
+	AuditLogActionEmailChange            = "EMAIL_CHANGE"
+	AuditLogActionTotpEnroll             = "TOTP_ENROLL"
+	AuditLogActionTotpConfirm            = "TOTP_CONFIRM"
+	AuditLogActionRecoveryCodeRegenerate = "RECOVERY_CODE_REGENERATE"
+	AuditLogActionPasskeyRegister        = "PASSKEY_REGISTER"
+	AuditLogActionPasskeyDelete          = "PASSKEY_DELETE"
+	AuditLogActionApiKeyCreate           = "API_KEY_CREATE"
+	AuditLogActionApiKeyRevoke           = "API_KEY_REVOKE"
+	AuditLogActionApiClientCreate        = "API_CLIENT_CREATE"
+	AuditLogActionApiClientDelete        = "API_CLIENT_DELETE"
+	AuditLogActionSessionRevoke          = "SESSION_REVOKE"
+	AuditLogActionSessionRefresh         = "SESSION_REFRESH"
+	AuditLogActionLogoutAll              = "LOGOUT_ALL"
+
+	AuditLogTargetUser      = "USER"
+	AuditLogTargetApiClient = "API_CLIENT"
+)
+
+// AuditLog records who did what to which target, the rows are append only
+type AuditLog struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ActorID    uint      `json:"actor_id"` // 0 when the action is done without authentication
+	Action     string    `json:"action"`
+	TargetType string    `json:"target_type"`
+	TargetID   uint      `json:"target_id"`
+	Changes    string    `json:"changes"` // JSON encoded map of field to its before and after value
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	RequestID  string    `json:"request_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
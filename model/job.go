@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+const (
+	JobStatePending   = "PENDING"
+	JobStateRunning   = "RUNNING"
+	JobStateRetrying  = "RETRYING"
+	JobStateCompleted = "COMPLETED"
+	JobStateFailed    = "FAILED"
+)
+
+// Job is the status of a task published with PublishJob, the ID is the asynq task id
+type Job struct {
+	ID         string     `json:"id" gorm:"primaryKey"`
+	Type       string     `json:"type"`
+	UserID     uint       `json:"user_id"` // The owner of the job, 0 for a job started by the system
+	State      string     `json:"state"`
+	Progress   int        `json:"progress"` // In percent
+	Result     string     `json:"result"`   // JSON encoded result payload
+	Error      string     `json:"error"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
@@ -11,10 +11,13 @@ type User struct {
 	Name              string         `json:"name"`
 	Email             string         `json:"email"`
 	PhoneNumber       string         `json:"phone_number"`
+	AvatarPath        string         `json:"avatar_path"`
 	EncryptedPassword string         `json:"encrypted_password"`
 	OtpSecret         string         `json:"otp_secret"`
 	IsActive          bool           `json:"is_active"`
 	IsVerified        bool           `json:"is_verified"`
+	PasswordChangedAt *time.Time     `json:"password_changed_at"`
+	Roles             []Role         `json:"roles" gorm:"many2many:user_roles"`
 	CreatedAt         time.Time      `json:"created_at"`
 	UpdatedAt         time.Time      `json:"updated_at"`
 	DeletedAt         gorm.DeletedAt `json:"deleted_at"`
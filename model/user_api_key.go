@@ -0,0 +1,30 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type UserApiKey struct {
+	ID         uint           `json:"id" gorm:"primaryKey"`
+	UserID     uint           `json:"user_id"`
+	Name       string         `json:"name"`
+	KeyPrefix  string         `json:"key_prefix"`
+	KeyHash    string         `json:"-"`
+	Scopes     string         `json:"scopes"` // Space separated, same format as ApiClient.Scopes
+	ExpiredAt  *time.Time     `json:"expired_at"`
+	LastUsedAt *time.Time     `json:"last_used_at"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `json:"deleted_at"`
+}
+
+func (userApiKey UserApiKey) GetScopes() []string {
+	return strings.Fields(userApiKey.Scopes)
+}
+
+func (userApiKey UserApiKey) IsExpired() bool {
+	return userApiKey.ExpiredAt != nil && time.Now().After(*userApiKey.ExpiredAt)
+}
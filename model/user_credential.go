@@ -0,0 +1,31 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type UserCredential struct {
+	ID              uint           `json:"id" gorm:"primaryKey"`
+	UserID          uint           `json:"user_id"`
+	Name            string         `json:"name"`
+	CredentialID    string         `json:"credential_id"` // Base64 raw url encoded
+	PublicKey       []byte         `json:"-"`
+	AttestationType string         `json:"attestation_type"`
+	Transports      string         `json:"transports"` // Space separated
+	Aaguid          []byte         `json:"-"`
+	SignCount       uint32         `json:"sign_count"`
+	CloneWarning    bool           `json:"clone_warning"`
+	BackupEligible  bool           `json:"backup_eligible"`
+	BackupState     bool           `json:"backup_state"`
+	LastUsedAt      *time.Time     `json:"last_used_at"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `json:"deleted_at"`
+}
+
+func (userCredential UserCredential) GetTransports() []string {
+	return strings.Fields(userCredential.Transports)
+}
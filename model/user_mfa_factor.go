@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type UserMfaFactor struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	UserID      uint           `json:"user_id"`
+	Type        string         `json:"type"`
+	Secret      string         `json:"secret"`
+	ConfirmedAt *time.Time     `json:"confirmed_at"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at"`
+}
+
+const (
+	UserMfaFactorTypeTotp = "TOTP"
+)
@@ -0,0 +1,29 @@
+package model
+
+const (
+	UserTransferTypeImport = "IMPORT"
+	UserTransferTypeExport = "EXPORT"
+
+	UserTransferFormatCsv  = "csv"
+	UserTransferFormatXlsx = "xlsx"
+)
+
+// UserTransfer is the result of a bulk user import or export job, it is stored JSON encoded in Job.Result
+// while the state, error and timestamps of the transfer are the ones of the job
+type UserTransfer struct {
+	Type       string `json:"type"`
+	Format     string `json:"format"`
+	Total      int    `json:"total"`
+	Processed  int    `json:"processed"`
+	Failed     int    `json:"failed"`
+	ResultPath string `json:"result_path"` // The exported file or the import error report
+	SourcePath string `json:"source_path"` // The uploaded import file, cleared once it is removed
+}
+
+// Progress is the processed rows in percent
+func (userTransfer UserTransfer) Progress() int {
+	if userTransfer.Total == 0 {
+		return 0
+	}
+	return userTransfer.Processed * 100 / userTransfer.Total
+}
@@ -11,6 +11,7 @@ type UserVerification struct {
 	Type      string         `json:"type"`
 	UserID    uint           `json:"user_id"`
 	Code      string         `json:"code"`
+	Target    string         `json:"target"` // The new value to apply once verified, e.g. the new email for CHANGE_EMAIL
 	ExpiredAt *time.Time     `json:"expired_at"`
 	UsedAt    *time.Time     `json:"used_at"`
 	CreatedAt time.Time      `json:"created_at"`
@@ -21,4 +22,14 @@ type UserVerification struct {
 const (
 	UserVerificationTypeVerifyAccount = "VERIFY_ACCOUNT"
 	UserVerificationTypeResetPassword = "RESET_PASSWORD"
+	UserVerificationTypeMagicLogin    = "MAGIC_LOGIN"
+	UserVerificationTypeChangeEmail   = "CHANGE_EMAIL"
 )
+
+func (userVerification UserVerification) IsExpired() bool {
+	return userVerification.ExpiredAt != nil && time.Now().After(*userVerification.ExpiredAt)
+}
+
+func (userVerification UserVerification) IsUsed() bool {
+	return userVerification.UsedAt != nil && !userVerification.UsedAt.IsZero()
+}
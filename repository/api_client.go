@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"app/model"
+	"app/request"
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+func (repo *Repository) GetApiClients(ctx context.Context, req request.GetApiClients) (res []model.ApiClient, total int64, err error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "GetApiClients")
+	defer span.Finish()
+
+	stmt := tx.Model(&model.ApiClient{})
+	if req.Search != "" {
+		search := fmt.Sprintf("%s%s%s", "%", req.Search, "%")
+		stmt = stmt.Where("name ILIKE ? OR client_id ILIKE ?", search, search)
+	}
+
+	err = stmt.Count(&total).Error
+	if err != nil {
+		return res, total, err
+	}
+
+	if req.GetOrderQuery() != "" {
+		stmt = stmt.Order(req.GetOrderQuery())
+	}
+
+	if req.Limit > 0 {
+		stmt = stmt.Limit(int(req.Limit))
+	}
+
+	if req.GetOffset() > 0 {
+		stmt = stmt.Offset(int(req.GetOffset()))
+	}
+
+	if len(req.Preloads) > 0 {
+		for _, preload := range req.Preloads {
+			stmt = stmt.Preload(preload)
+		}
+	}
+
+	err = stmt.Find(&res).Error
+	if err != nil {
+		return res, total, err
+	}
+
+	return res, total, nil
+}
+
+func (repo *Repository) GetApiClient(ctx context.Context, req request.GetApiClient) (res model.ApiClient, err error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "GetApiClient")
+	defer span.Finish()
+
+	stmt := tx.Model(&model.ApiClient{})
+	if req.ID > 0 {
+		stmt = stmt.Where("id = ?", req.ID)
+	}
+
+	if req.ClientID != "" {
+		stmt = stmt.Where("client_id = ?", req.ClientID)
+	}
+
+	if req.IsActive != nil {
+		stmt = stmt.Where("is_active = ?", *req.IsActive)
+	}
+
+	if len(req.Preloads) > 0 {
+		for _, preload := range req.Preloads {
+			stmt = stmt.Preload(preload)
+		}
+	}
+
+	err = stmt.First(&res).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (repo *Repository) CreateApiClient(ctx context.Context, apiClient model.ApiClient) (model.ApiClient, error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "CreateApiClient")
+	defer span.Finish()
+
+	err := tx.Create(&apiClient).Error
+	if err != nil {
+		return apiClient, err
+	}
+
+	return apiClient, nil
+}
+
+func (repo *Repository) DeleteApiClient(ctx context.Context, apiClient model.ApiClient) error {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "DeleteApiClient")
+	defer span.Finish()
+
+	return tx.Delete(&apiClient).Error
+}
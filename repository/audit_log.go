@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"app/model"
+	"app/request"
+	"context"
+)
+
+func (repo *Repository) GetAuditLogs(ctx context.Context, req request.GetAuditLogs) (res []model.AuditLog, total int64, err error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "GetAuditLogs")
+	defer span.Finish()
+
+	stmt := tx.Model(&model.AuditLog{})
+	if filterQuery, filterArgs := req.GetFilterQuery(); filterQuery != "" {
+		stmt = stmt.Where(filterQuery, filterArgs...)
+	}
+
+	err = stmt.Count(&total).Error
+	if err != nil {
+		return res, total, err
+	}
+
+	stmt = stmt.Order(req.GetOrderQuery())
+
+	if req.Limit > 0 {
+		stmt = stmt.Limit(int(req.Limit))
+	}
+
+	if req.GetOffset() > 0 {
+		stmt = stmt.Offset(int(req.GetOffset()))
+	}
+
+	err = stmt.Find(&res).Error
+	if err != nil {
+		return res, total, err
+	}
+
+	return res, total, nil
+}
+
+func (repo *Repository) CreateAuditLog(ctx context.Context, auditLog model.AuditLog) (model.AuditLog, error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "CreateAuditLog")
+	defer span.Finish()
+
+	err := tx.Create(&auditLog).Error
+	if err != nil {
+		return auditLog, err
+	}
+
+	return auditLog, nil
+}
+
+// ScrubUserAuditLogs remove the personal data of an erased user from the audit logs: the changes of the logs
+// targeting the user, and the client of every log targeting or done by the user. The rows are kept for the trail
+func (repo *Repository) ScrubUserAuditLogs(ctx context.Context, userId uint) error {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "ScrubUserAuditLogs")
+	defer span.Finish()
+
+	err := tx.Model(&model.AuditLog{}).
+		Where("target_type = ? AND target_id = ?", model.AuditLogTargetUser, userId).
+		Update("changes", "").Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Model(&model.AuditLog{}).
+		Where("(target_type = ? AND target_id = ?) OR actor_id = ?", model.AuditLogTargetUser, userId, userId).
+		Updates(map[string]any{
+			"ip_address": "",
+			"user_agent": "",
+		}).Error
+}
@@ -5,6 +5,7 @@ import (
 	"app/request"
 	"context"
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -30,6 +31,14 @@ func (repo *Repository) GetAuth(ctx context.Context, req request.GetAuth) (res m
 		stmt = stmt.Where("id = ?", req.ID)
 	}
 
+	if req.UserID > 0 {
+		stmt = stmt.Where("user_id = ?", req.UserID)
+	}
+
+	if req.AccessToken != "" {
+		stmt = stmt.Where("access_token = ?", req.AccessToken)
+	}
+
 	if req.RefreshToken != "" {
 		stmt = stmt.Where("refresh_token = ?", req.RefreshToken)
 	}
@@ -48,6 +57,49 @@ func (repo *Repository) GetAuth(ctx context.Context, req request.GetAuth) (res m
 	return res, nil
 }
 
+func (repo *Repository) GetAuths(ctx context.Context, req request.GetAuths) (res []model.UserAuth, err error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "GetAuths")
+	defer span.Finish()
+
+	stmt := tx.Model(&model.UserAuth{})
+	if req.UserID > 0 {
+		stmt = stmt.Where("user_id = ?", req.UserID)
+	}
+
+	if req.FamilyID != "" {
+		stmt = stmt.Where("family_id = ?", req.FamilyID)
+	}
+
+	if req.IsRotated != nil {
+		if *req.IsRotated {
+			stmt = stmt.Where("rotated_at IS NOT NULL")
+		} else {
+			stmt = stmt.Where("rotated_at IS NULL")
+		}
+	}
+
+	if req.IsActive != nil {
+		if *req.IsActive {
+			stmt = stmt.Where("refresh_token_expired_at > ?", time.Now())
+		} else {
+			stmt = stmt.Where("refresh_token_expired_at <= ?", time.Now())
+		}
+	}
+
+	if len(req.Preloads) > 0 {
+		for _, preload := range req.Preloads {
+			stmt = stmt.Preload(preload)
+		}
+	}
+
+	err = stmt.Order("created_at DESC").Find(&res).Error
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
 func (repo *Repository) UpdateAuth(ctx context.Context, auth model.UserAuth) (model.UserAuth, error) {
 	ctx, span, tx := repo.prepareRepoContext(ctx, "UpdateAuth")
 	defer span.Finish()
@@ -59,3 +111,51 @@ func (repo *Repository) UpdateAuth(ctx context.Context, auth model.UserAuth) (mo
 
 	return auth, nil
 }
+
+// RotateAuth mark the session as rotated only when it is not rotated yet, false is returned when another request
+// already rotated it so concurrent refreshes with the same token can't all succeed
+func (repo *Repository) RotateAuth(ctx context.Context, id uint, rotatedAt time.Time) (bool, error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "RotateAuth")
+	defer span.Finish()
+
+	res := tx.Model(&model.UserAuth{}).
+		Where("id = ? AND rotated_at IS NULL", id).
+		Updates(map[string]any{
+			"rotated_at": rotatedAt,
+			"updated_at": rotatedAt,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+// UpdateAuthLastUsedAt set last_used_at of the session owning the access token,
+// the update is skipped when last_used_at is still newer than the given threshold
+func (repo *Repository) UpdateAuthLastUsedAt(ctx context.Context, accessToken string, lastUsedAt time.Time, threshold time.Time) error {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "UpdateAuthLastUsedAt")
+	defer span.Finish()
+
+	return tx.Model(&model.UserAuth{}).
+		Where("access_token = ?", accessToken).
+		Where("last_used_at IS NULL OR last_used_at < ?", threshold).
+		Update("last_used_at", lastUsedAt).Error
+}
+
+func (repo *Repository) DeleteAuths(ctx context.Context, ids []uint) error {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "DeleteAuths")
+	defer span.Finish()
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var auth model.UserAuth
+	err := tx.Delete(&auth, ids).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"app/lib/auth"
+	"app/lib/cache"
 	"app/lib/constant"
 	"app/lib/logger"
 	"app/lib/signoz"
@@ -11,6 +12,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/go-webauthn/webauthn/webauthn"
 	"go.uber.org/zap"
 )
 
@@ -30,6 +32,15 @@ func (repo *Repository) GetVerificationDelayCacheWithTtl(ctx context.Context, us
 	return repo.cache.GetWithTtl(ctx, sendVerificationDelayKey)
 }
 
+// SetSessionTouchedFlag mark the session as recently used, false is returned when it was already marked
+func (repo *Repository) SetSessionTouchedFlag(ctx context.Context, accessToken string) (bool, error) {
+	ctx, span := signoz.StartSpan(ctx, "repository.SetSessionTouchedFlag")
+	defer span.Finish()
+
+	sessionTouchedKey := fmt.Sprintf(constant.SessionTouchedKeyPrefix, accessToken)
+	return repo.cache.SetNX(ctx, sessionTouchedKey, "default", constant.SessionLastUsedInterval*time.Second)
+}
+
 func (repo *Repository) SetMfaFlag(ctx context.Context, userId uint) error {
 	ctx, span := signoz.StartSpan(ctx, "repository.SetMfaFlag")
 	defer span.Finish()
@@ -46,6 +57,14 @@ func (repo *Repository) GetMfaFlag(ctx context.Context, userId uint) (string, er
 	return repo.cache.Get(ctx, mfaFlagKey)
 }
 
+func (repo *Repository) DelMfaFlag(ctx context.Context, userId uint) error {
+	ctx, span := signoz.StartSpan(ctx, "repository.DelMfaFlag")
+	defer span.Finish()
+
+	mfaFlagKey := fmt.Sprintf(constant.MfaFlagKeyPrefix, userId)
+	return repo.cache.Del(ctx, mfaFlagKey)
+}
+
 func (repo *Repository) SetAccessToken(ctx context.Context, accessToken string, claims auth.AccessTokenClaims) error {
 	ctx, span := signoz.StartSpan(ctx, "repository.SetAccessToken")
 	defer span.Finish()
@@ -61,7 +80,8 @@ func (repo *Repository) SetAccessToken(ctx context.Context, accessToken string,
 		return err
 	}
 
-	return repo.cache.Set(ctx, accessTokenKey, string(data), time.Duration(repo.config.ACCESS_TOKEN_TTL)*time.Second)
+	// The token is kept until its own expiry, mfa and api client tokens live shorter than ACCESS_TOKEN_TTL
+	return repo.cache.Set(ctx, accessTokenKey, string(data), time.Until(time.Unix(int64(claims.Exp), 0)))
 }
 
 func (repo *Repository) GetAccessToken(ctx context.Context, accessToken string) (auth.AccessTokenClaims, error) {
@@ -87,11 +107,26 @@ func (repo *Repository) GetAccessToken(ctx context.Context, accessToken string)
 	return claims, nil
 }
 
-func (repo *Repository) GetSendOtpRateLimitCtrWithTtl(ctx context.Context, identifier uint, otpType string) (int, time.Duration, error) {
+func (repo *Repository) DelAccessTokens(ctx context.Context, accessTokens ...string) error {
+	ctx, span := signoz.StartSpan(ctx, "repository.DelAccessTokens")
+	defer span.Finish()
+
+	if len(accessTokens) == 0 {
+		return nil
+	}
+
+	accessTokenKeys := []string{}
+	for _, accessToken := range accessTokens {
+		accessTokenKeys = append(accessTokenKeys, fmt.Sprintf(constant.AccessTokenKeyPrefix, accessToken))
+	}
+	return repo.cache.Del(ctx, accessTokenKeys...)
+}
+
+func (repo *Repository) GetSendOtpRateLimitCtrWithTtl(ctx context.Context, identifier uint, otpType, otpChannel string) (int, time.Duration, error) {
 	ctx, span := signoz.StartSpan(ctx, "repository.GetSendOtpRateLimitCtrWithTtl")
 	defer span.Finish()
 
-	otpRateLimitCtrKey := fmt.Sprintf(constant.SendOtpCtrKeyPrefix, identifier, otpType)
+	otpRateLimitCtrKey := fmt.Sprintf(constant.SendOtpCtrKeyPrefix, identifier, otpType, otpChannel)
 	ctrString, duration, err := repo.cache.GetWithTtl(ctx, otpRateLimitCtrKey)
 	if err != nil {
 		return 0, 0, err
@@ -112,34 +147,154 @@ func (repo *Repository) GetSendOtpRateLimitCtrWithTtl(ctx context.Context, ident
 	return ctr, duration, nil
 }
 
-func (repo *Repository) IncrSendOtpRateLimitCtr(ctx context.Context, identifier uint, otpType string) (int64, error) {
+func (repo *Repository) IncrSendOtpRateLimitCtr(ctx context.Context, identifier uint, otpType, otpChannel string) (int64, error) {
 	ctx, span := signoz.StartSpan(ctx, "repository.IncrSendOtpRateLimitCtr")
 	defer span.Finish()
 
-	otpRateLimitCtrKey := fmt.Sprintf(constant.SendOtpCtrKeyPrefix, identifier, otpType)
+	otpRateLimitCtrKey := fmt.Sprintf(constant.SendOtpCtrKeyPrefix, identifier, otpType, otpChannel)
 	return repo.cache.Incr(ctx, otpRateLimitCtrKey)
 }
 
-func (repo *Repository) ExpSendOtpRateLimitCtr(ctx context.Context, identifier uint, otpType string) error {
+func (repo *Repository) ExpSendOtpRateLimitCtr(ctx context.Context, identifier uint, otpType, otpChannel string) error {
 	ctx, span := signoz.StartSpan(ctx, "repository.ExpSendOtpRateLimitCtr")
 	defer span.Finish()
 
-	otpRateLimitCtrKey := fmt.Sprintf(constant.SendOtpCtrKeyPrefix, identifier, otpType)
+	otpRateLimitCtrKey := fmt.Sprintf(constant.SendOtpCtrKeyPrefix, identifier, otpType, otpChannel)
 	return repo.cache.Expire(ctx, otpRateLimitCtrKey, time.Duration(repo.config.SEND_OTP_MAX_RATE_LIMIT_TTL)*time.Second)
 }
 
-func (repo *Repository) TtlSendOtpDelay(ctx context.Context, identifier uint, otpType string) (time.Duration, error) {
+func (repo *Repository) TtlSendOtpDelay(ctx context.Context, identifier uint, otpType, otpChannel string) (time.Duration, error) {
 	ctx, span := signoz.StartSpan(ctx, "repository.TtlSendOtpDelay")
 	defer span.Finish()
 
-	sendOtpDelayKey := fmt.Sprintf(constant.SendOtpDelayKeyPrefix, identifier, otpType)
+	sendOtpDelayKey := fmt.Sprintf(constant.SendOtpDelayKeyPrefix, identifier, otpType, otpChannel)
 	return repo.cache.TTL(ctx, sendOtpDelayKey)
 }
 
-func (repo *Repository) SetSendOtpDelay(ctx context.Context, identifier uint, otpType string) error {
+func (repo *Repository) SetSendOtpDelay(ctx context.Context, identifier uint, otpType, otpChannel string) error {
 	ctx, span := signoz.StartSpan(ctx, "repository.SetSendOtpDelay")
 	defer span.Finish()
 
-	sendOtpDelayKey := fmt.Sprintf(constant.SendOtpDelayKeyPrefix, identifier, otpType)
+	sendOtpDelayKey := fmt.Sprintf(constant.SendOtpDelayKeyPrefix, identifier, otpType, otpChannel)
 	return repo.cache.Set(ctx, sendOtpDelayKey, "default", time.Duration(repo.config.SEND_OTP_DELAY_TTL)*time.Second)
 }
+
+func (repo *Repository) TtlLoginLock(ctx context.Context, scope, identifier string) (time.Duration, error) {
+	ctx, span := signoz.StartSpan(ctx, "repository.TtlLoginLock")
+	defer span.Finish()
+
+	loginLockKey := fmt.Sprintf(constant.LoginLockKeyPrefix, scope, identifier)
+	return repo.cache.TTL(ctx, loginLockKey)
+}
+
+func (repo *Repository) SetLoginLock(ctx context.Context, scope, identifier string, ttl time.Duration) error {
+	ctx, span := signoz.StartSpan(ctx, "repository.SetLoginLock")
+	defer span.Finish()
+
+	loginLockKey := fmt.Sprintf(constant.LoginLockKeyPrefix, scope, identifier)
+	return repo.cache.Set(ctx, loginLockKey, "default", ttl)
+}
+
+func (repo *Repository) IncrLoginFailedCtr(ctx context.Context, scope, identifier string) (int64, error) {
+	ctx, span := signoz.StartSpan(ctx, "repository.IncrLoginFailedCtr")
+	defer span.Finish()
+
+	loginFailedCtrKey := fmt.Sprintf(constant.LoginFailedCtrKeyPrefix, scope, identifier)
+	return repo.cache.Incr(ctx, loginFailedCtrKey)
+}
+
+func (repo *Repository) ExpLoginFailedCtr(ctx context.Context, scope, identifier string) error {
+	ctx, span := signoz.StartSpan(ctx, "repository.ExpLoginFailedCtr")
+	defer span.Finish()
+
+	loginFailedCtrKey := fmt.Sprintf(constant.LoginFailedCtrKeyPrefix, scope, identifier)
+	return repo.cache.Expire(ctx, loginFailedCtrKey, time.Duration(repo.config.LOGIN_FAILED_ATTEMPT_TTL)*time.Second)
+}
+
+func (repo *Repository) DelLoginFailedCtr(ctx context.Context, scope, identifier string) error {
+	ctx, span := signoz.StartSpan(ctx, "repository.DelLoginFailedCtr")
+	defer span.Finish()
+
+	loginFailedCtrKey := fmt.Sprintf(constant.LoginFailedCtrKeyPrefix, scope, identifier)
+	return repo.cache.Del(ctx, loginFailedCtrKey)
+}
+
+func (repo *Repository) IncrLoginLockLevel(ctx context.Context, scope, identifier string) (int64, error) {
+	ctx, span := signoz.StartSpan(ctx, "repository.IncrLoginLockLevel")
+	defer span.Finish()
+
+	loginLockLevelKey := fmt.Sprintf(constant.LoginLockLevelKeyPrefix, scope, identifier)
+	level, err := repo.cache.Incr(ctx, loginLockLevelKey)
+	if err != nil {
+		return 0, err
+	}
+
+	// The lockout level is forgotten once the account stays clean for the maximum lockout period
+	err = repo.cache.Expire(ctx, loginLockLevelKey, time.Duration(repo.config.LOGIN_LOCKOUT_MAX_TTL)*time.Second)
+	if err != nil {
+		return 0, err
+	}
+
+	return level, nil
+}
+
+func (repo *Repository) DelLoginLockLevel(ctx context.Context, scope, identifier string) error {
+	ctx, span := signoz.StartSpan(ctx, "repository.DelLoginLockLevel")
+	defer span.Finish()
+
+	loginLockLevelKey := fmt.Sprintf(constant.LoginLockLevelKeyPrefix, scope, identifier)
+	return repo.cache.Del(ctx, loginLockLevelKey)
+}
+
+func (repo *Repository) HitRateLimit(ctx context.Context, limiterName, identifier string, limit int, window time.Duration) (cache.RateLimitResult, error) {
+	ctx, span := signoz.StartSpan(ctx, "repository.HitRateLimit")
+	defer span.Finish()
+
+	rateLimitKey := fmt.Sprintf(constant.RateLimitKeyPrefix, limiterName, identifier)
+	return repo.cache.SlidingWindow(ctx, rateLimitKey, limit, window)
+}
+
+func (repo *Repository) SetWebauthnSession(ctx context.Context, ceremony, sessionId string, session webauthn.SessionData) error {
+	ctx, span := signoz.StartSpan(ctx, "repository.SetWebauthnSession")
+	defer span.Finish()
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		logger.LogError(ctx, "error json.Marshal", []zap.Field{
+			zap.Error(err),
+			zap.Strings("tags", []string{"repository", "SetWebauthnSession"}),
+		}...)
+		return err
+	}
+
+	webauthnSessionKey := fmt.Sprintf(constant.WebauthnSessionKeyPrefix, ceremony, sessionId)
+	return repo.cache.Set(ctx, webauthnSessionKey, string(data), time.Duration(repo.config.WEBAUTHN_SESSION_TTL)*time.Second)
+}
+
+// PopWebauthnSession get and delete the ceremony session so a challenge can only be answered once,
+// an empty session is returned when it is not found or already expired
+func (repo *Repository) PopWebauthnSession(ctx context.Context, ceremony, sessionId string) (webauthn.SessionData, error) {
+	ctx, span := signoz.StartSpan(ctx, "repository.PopWebauthnSession")
+	defer span.Finish()
+
+	webauthnSessionKey := fmt.Sprintf(constant.WebauthnSessionKeyPrefix, ceremony, sessionId)
+	sessionBytes, err := repo.cache.GetDelBytes(ctx, webauthnSessionKey)
+	if err != nil {
+		return webauthn.SessionData{}, err
+	}
+	if len(sessionBytes) == 0 {
+		return webauthn.SessionData{}, nil
+	}
+
+	var session webauthn.SessionData
+	err = json.Unmarshal(sessionBytes, &session)
+	if err != nil {
+		logger.LogError(ctx, "error json.Unmarshal", []zap.Field{
+			zap.Error(err),
+			zap.Strings("tags", []string{"repository", "PopWebauthnSession"}),
+		}...)
+		return webauthn.SessionData{}, err
+	}
+
+	return session, nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"app/model"
+	"app/request"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func (repo *Repository) CreateJob(ctx context.Context, job model.Job) (model.Job, error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "CreateJob")
+	defer span.Finish()
+
+	err := tx.Create(&job).Error
+	if err != nil {
+		return job, err
+	}
+
+	return job, nil
+}
+
+func (repo *Repository) GetJob(ctx context.Context, id string) (res model.Job, err error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "GetJob")
+	defer span.Finish()
+
+	err = tx.Model(&model.Job{}).Where("id = ?", id).First(&res).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (repo *Repository) GetJobs(ctx context.Context, req request.GetJobs) (res []model.Job, err error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "GetJobs")
+	defer span.Finish()
+
+	stmt := tx.Model(&model.Job{})
+	if req.UserID > 0 {
+		stmt = stmt.Where("user_id = ?", req.UserID)
+	}
+	if len(req.Types) > 0 {
+		stmt = stmt.Where("type IN ?", req.Types)
+	}
+
+	err = stmt.Find(&res).Error
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (repo *Repository) UpdateJob(ctx context.Context, job model.Job) (model.Job, error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "UpdateJob")
+	defer span.Finish()
+
+	err := tx.Save(&job).Error
+	if err != nil {
+		return job, err
+	}
+
+	return job, nil
+}
+
+// DeleteStaleJobs permanently delete the jobs finished before the threshold, pending or running jobs are kept
+func (repo *Repository) DeleteStaleJobs(ctx context.Context, threshold time.Time) (int64, error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "DeleteStaleJobs")
+	defer span.Finish()
+
+	result := tx.Where("finished_at < ?", threshold).Delete(&model.Job{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"app/lib"
+	"app/lib/logger"
+	"app/lib/oidc"
+	"app/lib/signoz"
+	"context"
+
+	"go.uber.org/zap"
+)
+
+func (repo *Repository) VerifyOidcIDToken(ctx context.Context, providerName, idToken string) (*oidc.Claims, error) {
+	ctx, span := signoz.StartSpan(ctx, "repository.VerifyOidcIDToken")
+	defer span.Finish()
+
+	provider, ok := repo.oidc[providerName]
+	if !ok {
+		notFoundError := lib.ErrorNotFound
+		notFoundError.Message = "SSO Provider Not Found"
+		return nil, notFoundError
+	}
+
+	claims, err := provider.Verify(ctx, idToken)
+	if err != nil {
+		logger.LogError(ctx, "error verify oidc id token", []zap.Field{
+			zap.Error(err),
+			zap.String("provider", providerName),
+			zap.Strings("tags", []string{"repository", "VerifyOidcIDToken"}),
+		}...)
+		return nil, lib.ErrorUnauthorized
+	}
+
+	return claims, nil
+}
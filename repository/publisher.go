@@ -1,17 +1,22 @@
 package repository
 
 import (
+	"app/lib"
+	"app/lib/constant"
 	"app/lib/logger"
 	"app/lib/signoz"
+	"app/model"
 	"context"
 	"encoding/json"
+	"fmt"
+	"slices"
 
 	"github.com/hibiken/asynq"
 	"go.uber.org/zap"
 )
 
 // PublishTask create new asynq task and publish it
-func (repo *Repository) PublishTask(ctx context.Context, taskType string, payload any) error {
+func (repo *Repository) PublishTask(ctx context.Context, taskType string, payload any, opts ...asynq.Option) error {
 	ctx, span := signoz.StartSpan(ctx, "repository.PublishTask")
 	defer span.Finish()
 
@@ -25,7 +30,7 @@ func (repo *Repository) PublishTask(ctx context.Context, taskType string, payloa
 	}
 
 	task := asynq.NewTask(taskType, jsonPayload)
-	taskInfo, err := repo.publisher.Enqueue(task)
+	taskInfo, err := repo.publisher.Enqueue(task, opts...)
 	if err != nil {
 		logger.LogError(ctx, "failed enqueue task", []zap.Field{
 			zap.Error(err),
@@ -42,3 +47,43 @@ func (repo *Repository) PublishTask(ctx context.Context, taskType string, payloa
 	}...)
 	return nil
 }
+
+// PublishJob create the job status and publish the task with the job id as the task id, so the worker can update
+// the status while processing it. When job.ID is empty a new id is generated.
+// The job is inserted before the task is published, call it outside of a transaction so the worker can find it.
+// The task type must be one of constant.JobTaskTypes, the worker does not keep the status of any other task
+func (repo *Repository) PublishJob(ctx context.Context, job model.Job, payload any) (model.Job, error) {
+	ctx, span := signoz.StartSpan(ctx, "repository.PublishJob")
+	defer span.Finish()
+
+	if !slices.Contains(constant.JobTaskTypes, job.Type) {
+		return job, fmt.Errorf("task type %s is not a job task type", job.Type)
+	}
+
+	if job.ID == "" {
+		job.ID = lib.GenerateUUID()
+	}
+	job.State = model.JobStatePending
+
+	job, err := repo.CreateJob(ctx, job)
+	if err != nil {
+		return job, err
+	}
+
+	err = repo.PublishTask(ctx, job.Type, payload, asynq.TaskID(job.ID))
+	if err != nil {
+		// The task will never run, so the job is failed right away instead of staying pending
+		job.State = model.JobStateFailed
+		job.Error = err.Error()
+		_, updateErr := repo.UpdateJob(ctx, job)
+		if updateErr != nil {
+			logger.LogError(ctx, "failed update job", []zap.Field{
+				zap.Error(updateErr),
+				zap.Strings("tags", []string{"repository", "PublishJob"}),
+			}...)
+		}
+		return job, err
+	}
+
+	return job, nil
+}
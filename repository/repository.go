@@ -6,7 +6,9 @@ import (
 	"app/lib/cache"
 	"app/lib/logger"
 	"app/lib/mailer"
+	"app/lib/oidc"
 	"app/lib/signoz"
+	"app/lib/sms"
 	"app/lib/task"
 	"app/lib/websocket"
 	"context"
@@ -19,19 +21,23 @@ type Repository struct {
 	config    *config.Config
 	db        *lib.Database
 	mailer    *mailer.SMTP
+	smsSender sms.Sender
 	publisher *task.Publisher
 	cache     *cache.Cache
 	wsPool    *websocket.WebsocketPool
+	oidc      oidc.Providers
 }
 
-func NewRepository(config *config.Config, db *lib.Database, mailer *mailer.SMTP, publisher *task.Publisher, cache *cache.Cache, wsPool *websocket.WebsocketPool) Repository {
+func NewRepository(config *config.Config, db *lib.Database, mailer *mailer.SMTP, smsSender sms.Sender, publisher *task.Publisher, cache *cache.Cache, wsPool *websocket.WebsocketPool, oidcProviders oidc.Providers) Repository {
 	return Repository{
 		config:    config,
 		db:        db,
 		mailer:    mailer,
+		smsSender: smsSender,
 		publisher: publisher,
 		cache:     cache,
 		wsPool:    wsPool,
+		oidc:      oidcProviders,
 	}
 }
 
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"app/model"
+	"app/request"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+func (repo *Repository) GetRole(ctx context.Context, req request.GetRole) (res model.Role, err error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "GetRole")
+	defer span.Finish()
+
+	stmt := tx.Model(&model.Role{})
+	if req.ID > 0 {
+		stmt = stmt.Where("id = ?", req.ID)
+	}
+
+	if req.Slug != "" {
+		stmt = stmt.Where("slug = ?", req.Slug)
+	}
+
+	if len(req.Preloads) > 0 {
+		for _, preload := range req.Preloads {
+			stmt = stmt.Preload(preload)
+		}
+	}
+
+	err = stmt.First(&res).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// GetUserRoles get all roles (with its permissions) assigned to the user
+func (repo *Repository) GetUserRoles(ctx context.Context, userId uint) (res []model.Role, err error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "GetUserRoles")
+	defer span.Finish()
+
+	err = tx.Model(&model.Role{}).
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userId).
+		Preload("Permissions").
+		Find(&res).Error
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (repo *Repository) CreateUserRole(ctx context.Context, userRole model.UserRole) (model.UserRole, error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "CreateUserRole")
+	defer span.Finish()
+
+	err := tx.Create(&userRole).Error
+	if err != nil {
+		return userRole, err
+	}
+
+	return userRole, nil
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"app/lib/logger"
+	"app/lib/signoz"
+	"app/lib/sms"
+	"context"
+
+	"go.uber.org/zap"
+)
+
+func (repo *Repository) SendSms(ctx context.Context, param sms.SendMessageParam) error {
+	ctx, span := signoz.StartSpan(ctx, "repository.SendSms")
+	defer span.Finish()
+
+	err := repo.smsSender.SendMessage(ctx, param)
+	if err != nil {
+		logger.LogError(ctx, "error send sms", []zap.Field{
+			zap.Error(err),
+			zap.String("channel", param.Channel),
+			zap.Strings("tags", []string{"repository", "SendSms"}),
+		}...)
+		return err
+	}
+	return nil
+}
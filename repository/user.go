@@ -6,6 +6,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -14,12 +16,7 @@ func (repo *Repository) GetUsers(ctx context.Context, req request.GetUsers) (res
 	ctx, span, tx := repo.prepareRepoContext(ctx, "GetUsers")
 	defer span.Finish()
 
-	stmt := tx.Model(&model.User{})
-	if req.Search != "" {
-		search := fmt.Sprintf("%s%s%s", "%", req.Search, "%")
-		stmt = stmt.Where("name ILIKE ? OR email ILIKE ? OR phone_number ILIKE ?", search, search)
-	}
-
+	stmt := filterUsers(tx.Model(&model.User{}), req)
 	err = stmt.Count(&total).Error
 	if err != nil {
 		return res, total, err
@@ -51,11 +48,78 @@ func (repo *Repository) GetUsers(ctx context.Context, req request.GetUsers) (res
 	return res, total, nil
 }
 
+// GetUsersByCursor get a page of users with keyset pagination, the total is only counted when requested.
+// hasMore tells whether there are more rows after the page in the direction of the cursor
+func (repo *Repository) GetUsersByCursor(ctx context.Context, req request.GetUsers) (res []model.User, total int64, hasMore bool, err error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "GetUsersByCursor")
+	defer span.Finish()
+
+	cursorQuery, err := req.GetCursorQuery()
+	if err != nil {
+		return res, total, hasMore, err
+	}
+
+	stmt := filterUsers(tx.Model(&model.User{}), req)
+	if req.WithTotal {
+		err = stmt.Count(&total).Error
+		if err != nil {
+			return res, total, hasMore, err
+		}
+	}
+
+	if cursorQuery.Where != "" {
+		stmt = stmt.Where(cursorQuery.Where, cursorQuery.Args...)
+	}
+
+	// One more row is fetched to know whether another page exists
+	limit := int(req.GetCursorLimit())
+	stmt = stmt.Order(cursorQuery.Order).Limit(limit + 1)
+
+	if len(req.Preloads) > 0 {
+		for _, preload := range req.Preloads {
+			stmt = stmt.Preload(preload)
+		}
+	}
+
+	err = stmt.Find(&res).Error
+	if err != nil {
+		return res, total, hasMore, err
+	}
+
+	if len(res) > limit {
+		hasMore = true
+		res = res[:limit]
+	}
+	if cursorQuery.IsPrev {
+		slices.Reverse(res)
+	}
+
+	return res, total, hasMore, nil
+}
+
+// filterUsers apply the search and the filters shared by every user listing
+func filterUsers(stmt *gorm.DB, req request.GetUsers) *gorm.DB {
+	if req.Search != "" {
+		search := fmt.Sprintf("%s%s%s", "%", req.Search, "%")
+		stmt = stmt.Where("name ILIKE ? OR email ILIKE ? OR phone_number ILIKE ?", search, search, search)
+	}
+
+	if filterQuery, filterArgs := req.GetFilterQuery(); filterQuery != "" {
+		stmt = stmt.Where(filterQuery, filterArgs...)
+	}
+
+	return stmt
+}
+
 func (repo *Repository) GetUser(ctx context.Context, req request.GetUser) (res model.User, err error) {
 	ctx, span, tx := repo.prepareRepoContext(ctx, "GetUser")
 	defer span.Finish()
 
 	stmt := tx.Model(&model.User{})
+	if req.WithDeleted {
+		stmt = stmt.Unscoped()
+	}
+
 	if req.ID > 0 {
 		stmt = stmt.Where("id = ?", req.ID)
 	}
@@ -106,6 +170,16 @@ func (repo *Repository) UpdateUser(ctx context.Context, user model.User) (model.
 	return user, nil
 }
 
+// UpdateUserEncryptedPassword only update the password hash, used when rehashing so updated_at is left untouched
+func (repo *Repository) UpdateUserEncryptedPassword(ctx context.Context, id uint, encryptedPassword string) error {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "UpdateUserEncryptedPassword")
+	defer span.Finish()
+
+	return tx.Model(&model.User{}).
+		Where("id = ?", id).
+		UpdateColumn("encrypted_password", encryptedPassword).Error
+}
+
 func (repo *Repository) DeleteUser(ctx context.Context, id uint) error {
 	ctx, span, tx := repo.prepareRepoContext(ctx, "DeleteUser")
 	defer span.Finish()
@@ -118,3 +192,50 @@ func (repo *Repository) DeleteUser(ctx context.Context, id uint) error {
 
 	return nil
 }
+
+// EraseUser anonymise the personal data of a soft deleted user, the row itself is kept so references stay valid
+func (repo *Repository) EraseUser(ctx context.Context, id uint, anonymisedEmail string) error {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "EraseUser")
+	defer span.Finish()
+
+	return tx.Unscoped().Model(&model.User{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"name":                "Deleted User",
+			"email":               anonymisedEmail,
+			"phone_number":        "",
+			"avatar_path":         "",
+			"encrypted_password":  "",
+			"otp_secret":          "",
+			"is_active":           false,
+			"password_changed_at": nil,
+			"updated_at":          time.Now(),
+		}).Error
+}
+
+// DeleteUserRelations permanently delete every record referencing the user
+func (repo *Repository) DeleteUserRelations(ctx context.Context, userId uint) error {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "DeleteUserRelations")
+	defer span.Finish()
+
+	relations := []any{
+		&model.UserAuth{},
+		&model.UserVerification{},
+		&model.UserMfaFactor{},
+		&model.UserRecoveryCode{},
+		&model.UserIdentity{},
+		&model.UserApiKey{},
+		&model.UserCredential{},
+		&model.UserPasswordHistory{},
+		&model.UserRole{},
+		&model.Job{},
+	}
+	for _, relation := range relations {
+		err := tx.Unscoped().Where("user_id = ?", userId).Delete(relation).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
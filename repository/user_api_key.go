@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"app/model"
+	"app/request"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func (repo *Repository) CreateUserApiKey(ctx context.Context, userApiKey model.UserApiKey) (model.UserApiKey, error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "CreateUserApiKey")
+	defer span.Finish()
+
+	err := tx.Create(&userApiKey).Error
+	if err != nil {
+		return userApiKey, err
+	}
+
+	return userApiKey, nil
+}
+
+func (repo *Repository) GetUserApiKeys(ctx context.Context, req request.GetUserApiKeys) (res []model.UserApiKey, err error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "GetUserApiKeys")
+	defer span.Finish()
+
+	stmt := tx.Model(&model.UserApiKey{})
+	if req.UserID > 0 {
+		stmt = stmt.Where("user_id = ?", req.UserID)
+	}
+
+	if len(req.Preloads) > 0 {
+		for _, preload := range req.Preloads {
+			stmt = stmt.Preload(preload)
+		}
+	}
+
+	err = stmt.Order("created_at DESC").Find(&res).Error
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (repo *Repository) GetUserApiKey(ctx context.Context, req request.GetUserApiKey) (res model.UserApiKey, err error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "GetUserApiKey")
+	defer span.Finish()
+
+	stmt := tx.Model(&model.UserApiKey{})
+	if req.ID > 0 {
+		stmt = stmt.Where("id = ?", req.ID)
+	}
+
+	if req.UserID > 0 {
+		stmt = stmt.Where("user_id = ?", req.UserID)
+	}
+
+	if req.KeyHash != "" {
+		stmt = stmt.Where("key_hash = ?", req.KeyHash)
+	}
+
+	if len(req.Preloads) > 0 {
+		for _, preload := range req.Preloads {
+			stmt = stmt.Preload(preload)
+		}
+	}
+
+	err = stmt.First(&res).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (repo *Repository) UpdateUserApiKeyLastUsedAt(ctx context.Context, id uint, lastUsedAt time.Time, threshold time.Time) error {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "UpdateUserApiKeyLastUsedAt")
+	defer span.Finish()
+
+	return tx.Model(&model.UserApiKey{}).
+		Where("id = ?", id).
+		Where("last_used_at IS NULL OR last_used_at < ?", threshold).
+		Update("last_used_at", lastUsedAt).Error
+}
+
+func (repo *Repository) DeleteUserApiKey(ctx context.Context, userApiKey model.UserApiKey) error {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "DeleteUserApiKey")
+	defer span.Finish()
+
+	return tx.Delete(&userApiKey).Error
+}
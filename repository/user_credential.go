@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"app/model"
+	"app/request"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+func (repo *Repository) CreateUserCredential(ctx context.Context, userCredential model.UserCredential) (model.UserCredential, error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "CreateUserCredential")
+	defer span.Finish()
+
+	err := tx.Create(&userCredential).Error
+	if err != nil {
+		return userCredential, err
+	}
+
+	return userCredential, nil
+}
+
+func (repo *Repository) GetUserCredentials(ctx context.Context, req request.GetUserCredentials) (res []model.UserCredential, err error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "GetUserCredentials")
+	defer span.Finish()
+
+	stmt := tx.Model(&model.UserCredential{})
+	if req.UserID > 0 {
+		stmt = stmt.Where("user_id = ?", req.UserID)
+	}
+
+	if len(req.Preloads) > 0 {
+		for _, preload := range req.Preloads {
+			stmt = stmt.Preload(preload)
+		}
+	}
+
+	err = stmt.Order("created_at DESC").Find(&res).Error
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (repo *Repository) GetUserCredential(ctx context.Context, req request.GetUserCredential) (res model.UserCredential, err error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "GetUserCredential")
+	defer span.Finish()
+
+	stmt := tx.Model(&model.UserCredential{})
+	if req.ID > 0 {
+		stmt = stmt.Where("id = ?", req.ID)
+	}
+
+	if req.UserID > 0 {
+		stmt = stmt.Where("user_id = ?", req.UserID)
+	}
+
+	if req.CredentialID != "" {
+		stmt = stmt.Where("credential_id = ?", req.CredentialID)
+	}
+
+	if len(req.Preloads) > 0 {
+		for _, preload := range req.Preloads {
+			stmt = stmt.Preload(preload)
+		}
+	}
+
+	err = stmt.First(&res).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (repo *Repository) UpdateUserCredential(ctx context.Context, userCredential model.UserCredential) (model.UserCredential, error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "UpdateUserCredential")
+	defer span.Finish()
+
+	err := tx.Save(&userCredential).Error
+	if err != nil {
+		return userCredential, err
+	}
+
+	return userCredential, nil
+}
+
+func (repo *Repository) DeleteUserCredential(ctx context.Context, userCredential model.UserCredential) error {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "DeleteUserCredential")
+	defer span.Finish()
+
+	return tx.Delete(&userCredential).Error
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"app/model"
+	"app/request"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+func (repo *Repository) CreateUserIdentity(ctx context.Context, userIdentity model.UserIdentity) (model.UserIdentity, error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "CreateUserIdentity")
+	defer span.Finish()
+
+	err := tx.Create(&userIdentity).Error
+	if err != nil {
+		return userIdentity, err
+	}
+
+	return userIdentity, nil
+}
+
+func (repo *Repository) GetUserIdentity(ctx context.Context, req request.GetUserIdentity) (res model.UserIdentity, err error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "GetUserIdentity")
+	defer span.Finish()
+
+	stmt := tx.Model(&model.UserIdentity{})
+	if req.UserID > 0 {
+		stmt = stmt.Where("user_id = ?", req.UserID)
+	}
+
+	if req.Provider != "" {
+		stmt = stmt.Where("provider = ?", req.Provider)
+	}
+
+	if req.Subject != "" {
+		stmt = stmt.Where("subject = ?", req.Subject)
+	}
+
+	if len(req.Preloads) > 0 {
+		for _, preload := range req.Preloads {
+			stmt = stmt.Preload(preload)
+		}
+	}
+
+	err = stmt.First(&res).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return res, err
+	}
+
+	return res, nil
+}
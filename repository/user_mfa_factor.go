@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"app/model"
+	"app/request"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+func (repo *Repository) CreateUserMfaFactor(ctx context.Context, userMfaFactor model.UserMfaFactor) (model.UserMfaFactor, error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "CreateUserMfaFactor")
+	defer span.Finish()
+
+	err := tx.Create(&userMfaFactor).Error
+	if err != nil {
+		return userMfaFactor, err
+	}
+
+	return userMfaFactor, nil
+}
+
+func (repo *Repository) GetUserMfaFactor(ctx context.Context, req request.GetUserMfaFactor) (res model.UserMfaFactor, err error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "GetUserMfaFactor")
+	defer span.Finish()
+
+	stmt := tx.Model(&model.UserMfaFactor{})
+	if req.UserID > 0 {
+		stmt = stmt.Where("user_id = ?", req.UserID)
+	}
+
+	if req.Type != "" {
+		stmt = stmt.Where("type = ?", req.Type)
+	}
+
+	if req.IsConfirmed != nil {
+		if *req.IsConfirmed {
+			stmt = stmt.Where("confirmed_at IS NOT NULL")
+		} else {
+			stmt = stmt.Where("confirmed_at IS NULL")
+		}
+	}
+
+	if len(req.Preloads) > 0 {
+		for _, preload := range req.Preloads {
+			stmt = stmt.Preload(preload)
+		}
+	}
+
+	err = stmt.First(&res).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (repo *Repository) UpdateUserMfaFactor(ctx context.Context, userMfaFactor model.UserMfaFactor) (model.UserMfaFactor, error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "UpdateUserMfaFactor")
+	defer span.Finish()
+
+	err := tx.Save(&userMfaFactor).Error
+	if err != nil {
+		return userMfaFactor, err
+	}
+
+	return userMfaFactor, nil
+}
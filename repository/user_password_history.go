@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"app/model"
+	"app/request"
+	"context"
+)
+
+func (repo *Repository) CreateUserPasswordHistory(ctx context.Context, userPasswordHistory model.UserPasswordHistory) (model.UserPasswordHistory, error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "CreateUserPasswordHistory")
+	defer span.Finish()
+
+	err := tx.Create(&userPasswordHistory).Error
+	if err != nil {
+		return userPasswordHistory, err
+	}
+
+	return userPasswordHistory, nil
+}
+
+// GetUserPasswordHistories get the password histories of the user, newest first
+func (repo *Repository) GetUserPasswordHistories(ctx context.Context, req request.GetUserPasswordHistories) (res []model.UserPasswordHistory, err error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "GetUserPasswordHistories")
+	defer span.Finish()
+
+	stmt := tx.Model(&model.UserPasswordHistory{})
+	if req.UserID > 0 {
+		stmt = stmt.Where("user_id = ?", req.UserID)
+	}
+
+	if req.Limit > 0 {
+		stmt = stmt.Limit(req.Limit)
+	}
+
+	err = stmt.Order("created_at DESC, id DESC").Find(&res).Error
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// PruneUserPasswordHistories permanently delete the password histories of the user except the newest ones
+func (repo *Repository) PruneUserPasswordHistories(ctx context.Context, userId uint, keep int) error {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "PruneUserPasswordHistories")
+	defer span.Finish()
+
+	keepIds := tx.Model(&model.UserPasswordHistory{}).
+		Select("id").
+		Where("user_id = ?", userId).
+		Order("created_at DESC, id DESC").
+		Limit(keep)
+
+	return tx.Unscoped().
+		Where("user_id = ? AND id NOT IN (?)", userId, keepIds).
+		Delete(&model.UserPasswordHistory{}).Error
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"app/model"
+	"app/request"
+	"context"
+	"time"
+)
+
+func (repo *Repository) CreateUserRecoveryCodes(ctx context.Context, userRecoveryCodes []model.UserRecoveryCode) ([]model.UserRecoveryCode, error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "CreateUserRecoveryCodes")
+	defer span.Finish()
+
+	err := tx.Create(&userRecoveryCodes).Error
+	if err != nil {
+		return userRecoveryCodes, err
+	}
+
+	return userRecoveryCodes, nil
+}
+
+func (repo *Repository) GetUserRecoveryCodes(ctx context.Context, req request.GetUserRecoveryCodes) (res []model.UserRecoveryCode, err error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "GetUserRecoveryCodes")
+	defer span.Finish()
+
+	stmt := tx.Model(&model.UserRecoveryCode{})
+	if req.UserID > 0 {
+		stmt = stmt.Where("user_id = ?", req.UserID)
+	}
+
+	if req.IsUsed != nil {
+		if *req.IsUsed {
+			stmt = stmt.Where("used_at IS NOT NULL")
+		} else {
+			stmt = stmt.Where("used_at IS NULL")
+		}
+	}
+
+	if len(req.Preloads) > 0 {
+		for _, preload := range req.Preloads {
+			stmt = stmt.Preload(preload)
+		}
+	}
+
+	err = stmt.Find(&res).Error
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// UseUserRecoveryCode mark the recovery code as used only when it is still unused, false is returned when
+// another request used it in the meantime so a code can't be redeemed twice
+func (repo *Repository) UseUserRecoveryCode(ctx context.Context, id uint, usedAt time.Time) (bool, error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "UseUserRecoveryCode")
+	defer span.Finish()
+
+	res := tx.Model(&model.UserRecoveryCode{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Updates(map[string]any{
+			"used_at":    usedAt,
+			"updated_at": usedAt,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+func (repo *Repository) DeleteUserRecoveryCodes(ctx context.Context, userId uint) error {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "DeleteUserRecoveryCodes")
+	defer span.Finish()
+
+	err := tx.Where("user_id = ?", userId).Delete(&model.UserRecoveryCode{}).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
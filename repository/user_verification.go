@@ -5,6 +5,7 @@ import (
 	"app/request"
 	"context"
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -46,6 +47,14 @@ func (repo *Repository) GetUserVerification(ctx context.Context, req request.Get
 		}
 	}
 
+	if req.IsExpired != nil {
+		if *req.IsExpired {
+			stmt = stmt.Where("expired_at <= ?", time.Now())
+		} else {
+			stmt = stmt.Where("expired_at IS NULL OR expired_at > ?", time.Now())
+		}
+	}
+
 	if len(req.Preloads) > 0 {
 		for _, preload := range req.Preloads {
 			stmt = stmt.Preload(preload)
@@ -71,3 +80,56 @@ func (repo *Repository) UpdateUserVerification(ctx context.Context, userVerifica
 
 	return userVerification, nil
 }
+
+// UseUserVerification mark the verification as used only when it is still unused, false is returned when
+// another request used it in the meantime so a code can't be redeemed twice
+func (repo *Repository) UseUserVerification(ctx context.Context, id uint, usedAt time.Time) (bool, error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "UseUserVerification")
+	defer span.Finish()
+
+	res := tx.Model(&model.UserVerification{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Updates(map[string]any{
+			"used_at":    usedAt,
+			"updated_at": usedAt,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+// ExpireUserVerification expire the verification only when it is still unused, false is returned when
+// another request used it in the meantime
+func (repo *Repository) ExpireUserVerification(ctx context.Context, id uint, expiredAt time.Time) (bool, error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "ExpireUserVerification")
+	defer span.Finish()
+
+	res := tx.Model(&model.UserVerification{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Updates(map[string]any{
+			"expired_at": expiredAt,
+			"updated_at": expiredAt,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+// DeleteStaleUserVerifications permanently delete verifications that were used or expired before the threshold
+func (repo *Repository) DeleteStaleUserVerifications(ctx context.Context, threshold time.Time) (int64, error) {
+	ctx, span, tx := repo.prepareRepoContext(ctx, "DeleteStaleUserVerifications")
+	defer span.Finish()
+
+	result := tx.Unscoped().
+		Where("used_at < ? OR expired_at < ?", threshold, threshold).
+		Delete(&model.UserVerification{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
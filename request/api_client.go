@@ -0,0 +1,66 @@
+package request
+
+import (
+	"app/lib/constant"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type GetApiClients struct {
+	BasePaginateRequest
+	Preloads []string
+}
+
+func (query *GetApiClients) GetOrderQuery() string {
+	fieldMap := map[string]string{
+		"name":       "name",
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+	}
+	return buildOrderQuery(query.Sort, fieldMap)
+}
+
+type GetApiClient struct {
+	ID       uint
+	ClientID string
+	IsActive *bool
+	Preloads []string
+}
+
+type CreateApiClient struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`
+	GrantedScopes []string // The permissions and scopes of the caller, the client scopes can not exceed them
+}
+
+func (r *CreateApiClient) Validate() error {
+	validationErrDetails := map[string]any{}
+
+	validateField(r.Name, "name", validationErrDetails, validation.Required)
+	for _, scope := range r.Scopes {
+		validateField(scope, "scopes", validationErrDetails, validation.Required, validation.Match(constant.ScopeRegex))
+	}
+
+	return buildValidationError(validationErrDetails)
+}
+
+type DeleteApiClient struct {
+	ID uint
+}
+
+type OauthToken struct {
+	GrantType    string
+	ClientID     string
+	ClientSecret string
+	Scope        string
+}
+
+func (r *OauthToken) Validate() error {
+	validationErrDetails := map[string]any{}
+
+	validateField(r.GrantType, "grant_type", validationErrDetails, validation.Required, validation.In(constant.GrantTypeClientCredentials))
+	validateField(r.ClientID, "client_id", validationErrDetails, validation.Required)
+	validateField(r.ClientSecret, "client_secret", validationErrDetails, validation.Required)
+
+	return buildValidationError(validationErrDetails)
+}
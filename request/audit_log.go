@@ -0,0 +1,30 @@
+package request
+
+type GetAuditLogs struct {
+	BasePaginateRequest
+}
+
+// GetOrderQuery default to the newest first since audit logs are mostly read backward
+func (query *GetAuditLogs) GetOrderQuery() string {
+	fieldMap := map[string]string{
+		"created_at": "created_at",
+	}
+	orderQuery := buildOrderQuery(query.Sort, fieldMap)
+	if orderQuery == "" {
+		return "created_at DESC, id DESC"
+	}
+	return orderQuery
+}
+
+func (query *GetAuditLogs) GetFilterQuery() (string, []any) {
+	fieldMap := map[string]string{
+		"actor_id":    "actor_id",
+		"action":      "action",
+		"target_type": "target_type",
+		"target_id":   "target_id",
+		"ip_address":  "ip_address",
+		"request_id":  "request_id",
+		"created_at":  "created_at",
+	}
+	return buildFilterQuery(query.Filters, fieldMap)
+}
@@ -1,6 +1,8 @@
 package request
 
 import (
+	"app/lib/constant"
+
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 )
@@ -72,7 +74,9 @@ type SendMfaOtp struct {
 }
 
 func (r *SendMfaOtp) Validate() error {
-	return nil
+	validationErrDetails := map[string]any{}
+	validateField(r.Channel, "channel", validationErrDetails, validation.In(constant.OtpChannelEmail, constant.OtpChannelSms, constant.OtpChannelWhatsapp))
+	return buildValidationError(validationErrDetails)
 }
 
 type SendOtp struct {
@@ -81,16 +85,36 @@ type SendOtp struct {
 }
 
 type ValidateMfaOtp struct {
-	OtpCode string `json:"otp_code"`
-	UserId  uint   `json:"user_id"`
+	OtpCode      string `json:"otp_code"`
+	RecoveryCode string `json:"recovery_code"`
+	UserId       uint   `json:"user_id"`
 }
 
 func (r *ValidateMfaOtp) Validate() error {
+	validationErrDetails := map[string]any{}
+	validateField(r.OtpCode, "otp_code", validationErrDetails, validation.When(r.RecoveryCode == "", validation.Required, validation.Length(6, 6), is.Digit))
+	return buildValidationError(validationErrDetails)
+}
+
+type EnrollTotp struct {
+	UserID uint
+}
+
+type ConfirmTotp struct {
+	OtpCode string `json:"otp_code"`
+	UserID  uint
+}
+
+func (r *ConfirmTotp) Validate() error {
 	validationErrDetails := map[string]any{}
 	validateField(r.OtpCode, "otp_code", validationErrDetails, validation.Required)
 	return buildValidationError(validationErrDetails)
 }
 
+type RegenerateRecoveryCodes struct {
+	UserID uint
+}
+
 type ForgotPassword struct {
 	Email string `json:"email"`
 }
@@ -115,11 +139,32 @@ func (r *ResetPassword) Validate() error {
 	return buildValidationError(validationErrDetails)
 }
 
-type SsoGoogle struct {
-	IdToken string `json:"id_token"`
+type SendMagicLink struct {
+	Email string `json:"email"`
+}
+
+func (r *SendMagicLink) Validate() error {
+	validationErrDetails := map[string]any{}
+	validateField(r.Email, "email", validationErrDetails, validation.Required, is.EmailFormat)
+	return buildValidationError(validationErrDetails)
+}
+
+type VerifyMagicLink struct {
+	Code string `json:"code"`
+}
+
+func (r *VerifyMagicLink) Validate() error {
+	validationErrDetails := map[string]any{}
+	validateField(r.Code, "code", validationErrDetails, validation.Required)
+	return buildValidationError(validationErrDetails)
+}
+
+type SsoLogin struct {
+	Provider string `json:"-"`
+	IdToken  string `json:"id_token"`
 }
 
-func (r *SsoGoogle) Validate() error {
+func (r *SsoLogin) Validate() error {
 	validationErrDetails := map[string]any{}
 	validateField(r.IdToken, "id_token", validationErrDetails, validation.Required)
 	return buildValidationError(validationErrDetails)
@@ -127,10 +172,44 @@ func (r *SsoGoogle) Validate() error {
 
 type GetAuth struct {
 	ID           uint
+	UserID       uint
+	AccessToken  string
 	RefreshToken string
 	Preloads     []string
 }
 
+type GetAuths struct {
+	UserID    uint
+	FamilyID  string
+	IsActive  *bool
+	IsRotated *bool
+	Preloads  []string
+}
+
+type GetSessions struct {
+	UserID      uint
+	AccessToken string
+}
+
+type RevokeSession struct {
+	ID     uint
+	UserID uint
+}
+
+type Logout struct {
+	ClearMfaFlag bool `json:"clear_mfa_flag"`
+	UserID       uint
+	AccessToken  string
+}
+
+func (r *Logout) Validate() error {
+	return nil
+}
+
+type LogoutAll struct {
+	UserID uint
+}
+
 type BasicAuth struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
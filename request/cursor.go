@@ -0,0 +1,128 @@
+package request
+
+import (
+	"app/lib"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	PaginationCursor = "cursor"
+
+	DefaultCursorLimit = 20
+	MaxCursorLimit     = 100
+)
+
+// Cursor is the position of a row in keyset pagination, it is encoded as an opaque base64 string.
+// Sort is the sort key the cursor was built with, a cursor can not be reused with another sort
+type Cursor struct {
+	Sort   string `json:"s"`
+	Value  any    `json:"v"`
+	ID     uint   `json:"id"`
+	IsPrev bool   `json:"p,omitempty"`
+}
+
+func EncodeCursor(cursor Cursor) string {
+	bytes, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(bytes)
+}
+
+func DecodeCursor(s string) (cursor Cursor, err error) {
+	parseQueryError := lib.ErrorParseQuery
+	parseQueryError.ErrDetails = map[string]any{
+		"cursor": "invalid cursor",
+	}
+
+	bytes, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cursor, parseQueryError
+	}
+
+	err = json.Unmarshal(bytes, &cursor)
+	if err != nil {
+		return cursor, parseQueryError
+	}
+
+	// The value is bound as a query argument, a tampered array or object must not reach the database
+	switch cursor.Value.(type) {
+	case nil, string, float64:
+	default:
+		return Cursor{}, parseQueryError
+	}
+
+	return cursor, nil
+}
+
+// CursorQuery is the keyset condition and order of a cursor page, rows must be reversed when IsPrev is true
+// since a previous page is fetched in the opposite order
+type CursorQuery struct {
+	SortKey string
+	Where   string
+	Args    []any
+	Order   string
+	IsPrev  bool
+}
+
+// buildCursorQuery build the keyset query from the first valid sort and the cursor, id is always used as tie breaker.
+// Without a valid sort the rows are ordered by id
+func buildCursorQuery(querySort []string, rawCursor string, fieldMap map[string]string) (res CursorQuery, err error) {
+	sortKey, fieldName, isDesc := "id", "id", false
+	for _, s := range querySort {
+		if len(s) == 0 {
+			continue
+		}
+
+		key, desc := s, false
+		if s[len(s)-1:] == "-" {
+			key, desc = s[:len(s)-1], true
+		}
+
+		if name, ok := fieldMap[key]; ok {
+			sortKey, fieldName, isDesc = key, name, desc
+			break
+		}
+	}
+	res.SortKey = sortKey
+
+	cursor := Cursor{}
+	if rawCursor != "" {
+		cursor, err = DecodeCursor(rawCursor)
+		if err != nil {
+			return res, err
+		}
+		if cursor.Sort != sortKey || (fieldName != "id" && cursor.Value == nil) {
+			parseQueryError := lib.ErrorParseQuery
+			parseQueryError.ErrDetails = map[string]any{
+				"cursor": "cursor does not match the sort",
+			}
+			return res, parseQueryError
+		}
+	}
+	res.IsPrev = cursor.IsPrev
+
+	// A previous page walks backward, so both the comparison and the order are flipped
+	isBackward := isDesc != cursor.IsPrev
+	comparison, order := ">", "ASC"
+	if isBackward {
+		comparison, order = "<", "DESC"
+	}
+
+	if rawCursor != "" {
+		if fieldName == "id" {
+			res.Where = fmt.Sprintf("id %s ?", comparison)
+			res.Args = []any{cursor.ID}
+		} else {
+			res.Where = fmt.Sprintf("(%s, id) %s (?, ?)", fieldName, comparison)
+			res.Args = []any{cursor.Value, cursor.ID}
+		}
+	}
+
+	if fieldName == "id" {
+		res.Order = fmt.Sprintf("id %s", order)
+	} else {
+		res.Order = fmt.Sprintf("%s %s, id %s", fieldName, order, order)
+	}
+
+	return res, nil
+}
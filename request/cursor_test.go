@@ -0,0 +1,185 @@
+package request
+
+import (
+	"app/lib"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestEncodeDecodeCursor(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{name: "id", cursor: Cursor{Sort: "id", ID: 10}},
+		{name: "string value", cursor: Cursor{Sort: "name", Value: "john", ID: 10}},
+		{name: "number value", cursor: Cursor{Sort: "age", Value: float64(30), ID: 10, IsPrev: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCursor(EncodeCursor(tt.cursor))
+			if err != nil {
+				t.Fatalf("err = %v, want nil", err)
+			}
+			if !reflect.DeepEqual(got, tt.cursor) {
+				t.Errorf("cursor = %#v, want %#v", got, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{name: "not base64", raw: "%%%"},
+		{name: "not json", raw: base64.RawURLEncoding.EncodeToString([]byte("cursor"))},
+		{name: "array value", raw: base64.RawURLEncoding.EncodeToString([]byte(`{"s":"name","v":["a"],"id":1}`))},
+		{name: "object value", raw: base64.RawURLEncoding.EncodeToString([]byte(`{"s":"name","v":{"a":1},"id":1}`))},
+		{name: "bool value", raw: base64.RawURLEncoding.EncodeToString([]byte(`{"s":"name","v":true,"id":1}`))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeCursor(tt.raw)
+			assertParseQueryError(t, err)
+		})
+	}
+}
+
+func TestBuildCursorQuery(t *testing.T) {
+	fieldMap := map[string]string{
+		"name":       "users.name",
+		"created_at": "users.created_at",
+	}
+
+	tests := []struct {
+		name      string
+		querySort []string
+		cursor    string
+		want      CursorQuery
+	}{
+		{
+			name: "default sort by id",
+			want: CursorQuery{SortKey: "id", Order: "id ASC"},
+		},
+		{
+			name:      "unknown sort fallback to id",
+			querySort: []string{"password"},
+			want:      CursorQuery{SortKey: "id", Order: "id ASC"},
+		},
+		{
+			name:      "first valid sort is used",
+			querySort: []string{"", "password", "created_at-", "name"},
+			want:      CursorQuery{SortKey: "created_at", Order: "users.created_at DESC, id DESC"},
+		},
+		{
+			name:   "next page by id",
+			cursor: EncodeCursor(Cursor{Sort: "id", ID: 10}),
+			want:   CursorQuery{SortKey: "id", Where: "id > ?", Args: []any{uint(10)}, Order: "id ASC"},
+		},
+		{
+			name:      "next page by field",
+			querySort: []string{"name"},
+			cursor:    EncodeCursor(Cursor{Sort: "name", Value: "john", ID: 10}),
+			want: CursorQuery{
+				SortKey: "name",
+				Where:   "(users.name, id) > (?, ?)",
+				Args:    []any{"john", uint(10)},
+				Order:   "users.name ASC, id ASC",
+			},
+		},
+		{
+			name:      "next page by field descending",
+			querySort: []string{"name-"},
+			cursor:    EncodeCursor(Cursor{Sort: "name", Value: "john", ID: 10}),
+			want: CursorQuery{
+				SortKey: "name",
+				Where:   "(users.name, id) < (?, ?)",
+				Args:    []any{"john", uint(10)},
+				Order:   "users.name DESC, id DESC",
+			},
+		},
+		{
+			name:      "previous page flip the order",
+			querySort: []string{"name"},
+			cursor:    EncodeCursor(Cursor{Sort: "name", Value: "john", ID: 10, IsPrev: true}),
+			want: CursorQuery{
+				SortKey: "name",
+				Where:   "(users.name, id) < (?, ?)",
+				Args:    []any{"john", uint(10)},
+				Order:   "users.name DESC, id DESC",
+				IsPrev:  true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildCursorQuery(tt.querySort, tt.cursor, fieldMap)
+			if err != nil {
+				t.Fatalf("err = %v, want nil", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cursor query = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildCursorQueryInvalid(t *testing.T) {
+	fieldMap := map[string]string{
+		"name": "users.name",
+	}
+
+	tests := []struct {
+		name      string
+		querySort []string
+		cursor    string
+	}{
+		{name: "invalid cursor", cursor: "%%%"},
+		{name: "cursor of another sort", querySort: []string{"name"}, cursor: EncodeCursor(Cursor{Sort: "id", ID: 10})},
+		{name: "missing value", querySort: []string{"name"}, cursor: EncodeCursor(Cursor{Sort: "name", ID: 10})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := buildCursorQuery(tt.querySort, tt.cursor, fieldMap)
+			assertParseQueryError(t, err)
+		})
+	}
+}
+
+func assertParseQueryError(t *testing.T, err error) {
+	t.Helper()
+
+	var customError lib.CustomError
+	if !errors.As(err, &customError) || customError.Code != lib.ErrorParseQuery.Code {
+		t.Errorf("err = %v, want parse query error", err)
+	}
+}
+
+func TestGetCursorLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit uint
+		want  uint
+	}{
+		{name: "default", limit: 0, want: DefaultCursorLimit},
+		{name: "within the max", limit: 50, want: 50},
+		{name: "capped to the max", limit: 1000, want: MaxCursorLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := BasePaginateRequest{Limit: tt.limit}
+			if got := query.GetCursorLimit(); got != tt.want {
+				t.Errorf("limit = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
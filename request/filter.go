@@ -0,0 +1,66 @@
+package request
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	FilterOperatorEq      = "eq"
+	FilterOperatorIn      = "in"
+	FilterOperatorGte     = "gte"
+	FilterOperatorLte     = "lte"
+	FilterOperatorBetween = "between"
+	FilterOperatorIsNull  = "is_null"
+)
+
+// Filter is a single condition parsed from the query string, the format is field[operator]=value
+// and field=value is a shorthand of the eq operator.
+// Values holds one value for eq, gte, lte and is_null, two values for between and any number of values for in.
+type Filter struct {
+	Field    string `json:"field"`
+	Operator string `json:"operator"`
+	Values   []any  `json:"values"`
+}
+
+// buildFilterQuery build the where clause of the filters, fields which are not in the fieldMap are skipped
+func buildFilterQuery(filters []Filter, fieldMap map[string]string) (string, []any) {
+	conditions := []string{}
+	args := []any{}
+	for _, filter := range filters {
+		fieldName, ok := fieldMap[filter.Field]
+		if !ok || len(filter.Values) == 0 {
+			continue
+		}
+
+		switch filter.Operator {
+		case FilterOperatorEq:
+			conditions = append(conditions, fmt.Sprintf("%s = ?", fieldName))
+			args = append(args, filter.Values[0])
+		case FilterOperatorIn:
+			conditions = append(conditions, fmt.Sprintf("%s IN ?", fieldName))
+			args = append(args, filter.Values)
+		case FilterOperatorGte:
+			conditions = append(conditions, fmt.Sprintf("%s >= ?", fieldName))
+			args = append(args, filter.Values[0])
+		case FilterOperatorLte:
+			conditions = append(conditions, fmt.Sprintf("%s <= ?", fieldName))
+			args = append(args, filter.Values[0])
+		case FilterOperatorBetween:
+			if len(filter.Values) != 2 {
+				continue
+			}
+			conditions = append(conditions, fmt.Sprintf("%s BETWEEN ? AND ?", fieldName))
+			args = append(args, filter.Values[0], filter.Values[1])
+		case FilterOperatorIsNull:
+			isNull, _ := filter.Values[0].(bool)
+			if isNull {
+				conditions = append(conditions, fmt.Sprintf("%s IS NULL", fieldName))
+			} else {
+				conditions = append(conditions, fmt.Sprintf("%s IS NOT NULL", fieldName))
+			}
+		}
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
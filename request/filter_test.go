@@ -0,0 +1,102 @@
+package request
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildFilterQuery(t *testing.T) {
+	fieldMap := map[string]string{
+		"name":       "users.name",
+		"is_active":  "users.is_active",
+		"created_at": "users.created_at",
+		"deleted_at": "users.deleted_at",
+	}
+
+	tests := []struct {
+		name      string
+		filters   []Filter
+		wantQuery string
+		wantArgs  []any
+	}{
+		{
+			name:      "no filter",
+			filters:   []Filter{},
+			wantQuery: "",
+			wantArgs:  []any{},
+		},
+		{
+			name:      "eq",
+			filters:   []Filter{{Field: "name", Operator: FilterOperatorEq, Values: []any{"john"}}},
+			wantQuery: "users.name = ?",
+			wantArgs:  []any{"john"},
+		},
+		{
+			name:      "in",
+			filters:   []Filter{{Field: "name", Operator: FilterOperatorIn, Values: []any{"john", "jane"}}},
+			wantQuery: "users.name IN ?",
+			wantArgs:  []any{[]any{"john", "jane"}},
+		},
+		{
+			name: "gte and lte",
+			filters: []Filter{
+				{Field: "created_at", Operator: FilterOperatorGte, Values: []any{"2025-01-01"}},
+				{Field: "created_at", Operator: FilterOperatorLte, Values: []any{"2025-12-31"}},
+			},
+			wantQuery: "users.created_at >= ? AND users.created_at <= ?",
+			wantArgs:  []any{"2025-01-01", "2025-12-31"},
+		},
+		{
+			name:      "between",
+			filters:   []Filter{{Field: "created_at", Operator: FilterOperatorBetween, Values: []any{"2025-01-01", "2025-12-31"}}},
+			wantQuery: "users.created_at BETWEEN ? AND ?",
+			wantArgs:  []any{"2025-01-01", "2025-12-31"},
+		},
+		{
+			name:      "between without two values is skipped",
+			filters:   []Filter{{Field: "created_at", Operator: FilterOperatorBetween, Values: []any{"2025-01-01"}}},
+			wantQuery: "",
+			wantArgs:  []any{},
+		},
+		{
+			name:      "is null",
+			filters:   []Filter{{Field: "deleted_at", Operator: FilterOperatorIsNull, Values: []any{true}}},
+			wantQuery: "users.deleted_at IS NULL",
+			wantArgs:  []any{},
+		},
+		{
+			name:      "is not null",
+			filters:   []Filter{{Field: "deleted_at", Operator: FilterOperatorIsNull, Values: []any{false}}},
+			wantQuery: "users.deleted_at IS NOT NULL",
+			wantArgs:  []any{},
+		},
+		{
+			name: "unknown field and empty values are skipped",
+			filters: []Filter{
+				{Field: "password", Operator: FilterOperatorEq, Values: []any{"secret"}},
+				{Field: "name", Operator: FilterOperatorEq, Values: []any{}},
+				{Field: "is_active", Operator: FilterOperatorEq, Values: []any{true}},
+			},
+			wantQuery: "users.is_active = ?",
+			wantArgs:  []any{true},
+		},
+		{
+			name:      "unknown operator is skipped",
+			filters:   []Filter{{Field: "name", Operator: "like", Values: []any{"john"}}},
+			wantQuery: "",
+			wantArgs:  []any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery, gotArgs := buildFilterQuery(tt.filters, fieldMap)
+			if gotQuery != tt.wantQuery {
+				t.Errorf("query = %q, want %q", gotQuery, tt.wantQuery)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("args = %#v, want %#v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
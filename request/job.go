@@ -0,0 +1,11 @@
+package request
+
+type GetJob struct {
+	ID     string
+	UserID uint
+}
+
+type GetJobs struct {
+	UserID uint
+	Types  []string
+}
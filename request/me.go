@@ -0,0 +1,78 @@
+package request
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+type ChangePassword struct {
+	UserID             uint
+	AccessToken        string
+	CurrentPassword    string `json:"current_password"`
+	NewPassword        string `json:"new_password"`
+	ConfirmNewPassword string `json:"confirm_new_password"`
+}
+
+func (r *ChangePassword) Validate() error {
+	validationErrDetails := map[string]any{}
+	validateField(r.CurrentPassword, "current_password", validationErrDetails, validation.Required)
+	validateField(r.NewPassword, "new_password", validationErrDetails, IsPassword...)
+	validateField(r.ConfirmNewPassword, "confirm_new_password", validationErrDetails, validation.By(isEqual(r.NewPassword, "new_password")))
+	return buildValidationError(validationErrDetails)
+}
+
+type ChangeEmail struct {
+	UserID          uint
+	NewEmail        string `json:"new_email"`
+	CurrentPassword string `json:"current_password"`
+}
+
+// Validate keep the current password optional, accounts created through sso have no password to confirm
+func (r *ChangeEmail) Validate() error {
+	validationErrDetails := map[string]any{}
+	validateField(r.NewEmail, "new_email", validationErrDetails, validation.Required, is.EmailFormat)
+	return buildValidationError(validationErrDetails)
+}
+
+type ConfirmChangeEmail struct {
+	Code string `json:"code"`
+}
+
+func (r *ConfirmChangeEmail) Validate() error {
+	validationErrDetails := map[string]any{}
+	validateField(r.Code, "code", validationErrDetails, validation.Required)
+	return buildValidationError(validationErrDetails)
+}
+
+type GetProfile struct {
+	UserID uint
+}
+
+type UpdateProfile struct {
+	UserID      uint
+	Name        string `json:"name"`
+	PhoneNumber string `json:"phone_number"`
+}
+
+func (r *UpdateProfile) Validate() error {
+	validationErrDetails := map[string]any{}
+	validateField(r.Name, "name", validationErrDetails, validation.Required)
+	validateField(r.PhoneNumber, "phone_number", validationErrDetails, validation.Required)
+	return buildValidationError(validationErrDetails)
+}
+
+type UploadAvatar struct {
+	FileData
+	UserID uint
+}
+
+type DeleteAccount struct {
+	UserID   uint
+	Password string `json:"password"`
+}
+
+// Validate keep the password optional, accounts created through sso have no password to confirm
+func (r *DeleteAccount) Validate() error {
+	validationErrDetails := map[string]any{}
+	return buildValidationError(validationErrDetails)
+}
@@ -0,0 +1,10 @@
+package request
+
+import "time"
+
+type HitRateLimit struct {
+	LimiterName string
+	Identifier  string
+	Limit       int
+	Window      time.Duration
+}
@@ -3,7 +3,6 @@ package request
 import (
 	"app/lib"
 	"fmt"
-	"regexp"
 	"strings"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
@@ -14,16 +13,34 @@ type Validator interface {
 }
 
 type BasePaginateRequest struct {
-	Sort   []string `json:"sort"`
-	Search string   `json:"search"`
-	Page   uint     `json:"page"`
-	Limit  uint     `json:"limit"`
+	Sort       []string `json:"sort"`
+	Search     string   `json:"search"`
+	Page       uint     `json:"page"`
+	Limit      uint     `json:"limit"`
+	Pagination string   `json:"pagination"` // Set to cursor to use keyset pagination instead of page
+	Cursor     string   `json:"cursor"`
+	WithTotal  bool     `json:"with_total"` // The total is only counted in cursor pagination when requested
+	Filters    []Filter `json:"-"`
 }
 
 func (query *BasePaginateRequest) GetOffset() uint {
+	if query.Page == 0 {
+		return 0
+	}
 	return (query.Page - 1) * query.Limit
 }
 
+func (query *BasePaginateRequest) IsCursorPagination() bool {
+	return query.Pagination == PaginationCursor
+}
+
+func (query *BasePaginateRequest) GetCursorLimit() uint {
+	if query.Limit == 0 {
+		return DefaultCursorLimit
+	}
+	return min(query.Limit, MaxCursorLimit)
+}
+
 func buildOrderQuery(querySort []string, fieldMap map[string]string) string {
 	result := []string{}
 	for _, s := range querySort {
@@ -64,13 +81,23 @@ func buildValidationError(errDetails map[string]any) error {
 	return validationError
 }
 
+// IsPassword only check the bcrypt input limit, the password policy is enforced by the usecase
+// since it is configured through app_settings
 var IsPassword = []validation.Rule{
 	validation.Required,
-	validation.Length(6, 0),
-	validation.Match(regexp.MustCompile(`[0-9]`)).Error("at least one number"),
-	validation.Match(regexp.MustCompile(`[a-zA-Z]`)).Error("at least one letter"),
-	validation.Match(regexp.MustCompile(`[!@#$%^&*()]`)).Error("at least one special character"),
-	validation.Match(regexp.MustCompile(`^[ a-zA-Z0-9!@#$%^&*()?]+$`)).Error("use only allowed special characters: !@#$%^&*()"),
+	validation.By(isMaxBytes(72)),
+}
+
+// isMaxBytes limit the length in bytes instead of runes, bcrypt reject an input longer than 72 bytes
+// and a multi byte password can be within 72 runes
+func isMaxBytes(max int) validation.RuleFunc {
+	return func(value any) error {
+		s, _ := value.(string)
+		if len(s) > max {
+			return fmt.Errorf("the length must be no more than %d bytes", max)
+		}
+		return nil
+	}
 }
 
 func isEqual(str, field string) validation.RuleFunc {
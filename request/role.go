@@ -0,0 +1,7 @@
+package request
+
+type GetRole struct {
+	ID       uint
+	Slug     string
+	Preloads []string
+}
@@ -20,11 +20,31 @@ func (query *GetUsers) GetOrderQuery() string {
 	return buildOrderQuery(query.Sort, fieldMap)
 }
 
+func (query *GetUsers) GetCursorQuery() (CursorQuery, error) {
+	fieldMap := map[string]string{
+		"name":       "name",
+		"email":      "email",
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+	}
+	return buildCursorQuery(query.Sort, query.Cursor, fieldMap)
+}
+
+func (query *GetUsers) GetFilterQuery() (string, []any) {
+	fieldMap := map[string]string{
+		"is_active":   "is_active",
+		"is_verified": "is_verified",
+		"created_at":  "created_at",
+	}
+	return buildFilterQuery(query.Filters, fieldMap)
+}
+
 type GetUser struct {
-	ID       uint
-	Name     string
-	Email    string
-	Preloads []string
+	ID          uint
+	Name        string
+	Email       string
+	WithDeleted bool
+	Preloads    []string
 }
 
 type CreateUser struct {
@@ -65,3 +85,11 @@ func (r *UpdateUser) Validate() error {
 type DeleteUser struct {
 	ID uint
 }
+
+type DeactivateUser struct {
+	ID uint
+}
+
+type ReactivateUser struct {
+	ID uint
+}
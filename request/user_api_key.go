@@ -0,0 +1,46 @@
+package request
+
+import (
+	"app/lib/constant"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type GetUserApiKeys struct {
+	UserID   uint
+	Preloads []string
+}
+
+type GetUserApiKey struct {
+	ID       uint
+	UserID   uint
+	KeyHash  string
+	Preloads []string
+}
+
+type CreateApiKey struct {
+	UserID    uint
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiredAt *time.Time `json:"expired_at"`
+}
+
+func (r *CreateApiKey) Validate() error {
+	validationErrDetails := map[string]any{}
+
+	validateField(r.Name, "name", validationErrDetails, validation.Required)
+	for _, scope := range r.Scopes {
+		validateField(scope, "scopes", validationErrDetails, validation.Required, validation.Match(constant.ScopeRegex))
+	}
+	if r.ExpiredAt != nil && !r.ExpiredAt.After(time.Now()) {
+		validationErrDetails["expired_at"] = "must be in the future"
+	}
+
+	return buildValidationError(validationErrDetails)
+}
+
+type RevokeApiKey struct {
+	ID     uint
+	UserID uint
+}
@@ -0,0 +1,56 @@
+package request
+
+import (
+	"encoding/json"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type GetUserCredentials struct {
+	UserID   uint
+	Preloads []string
+}
+
+type GetUserCredential struct {
+	ID           uint
+	UserID       uint
+	CredentialID string
+	Preloads     []string
+}
+
+type BeginWebauthn struct {
+	UserID uint
+}
+
+type FinishWebauthnRegistration struct {
+	UserID     uint
+	SessionID  string          `json:"session_id"`
+	Name       string          `json:"name"`
+	Credential json.RawMessage `json:"credential"`
+}
+
+func (r *FinishWebauthnRegistration) Validate() error {
+	validationErrDetails := map[string]any{}
+	validateField(r.SessionID, "session_id", validationErrDetails, validation.Required)
+	validateField(r.Name, "name", validationErrDetails, validation.Required, validation.Length(1, 255))
+	validateField(string(r.Credential), "credential", validationErrDetails, validation.Required)
+	return buildValidationError(validationErrDetails)
+}
+
+type FinishWebauthnLogin struct {
+	UserID     uint
+	SessionID  string          `json:"session_id"`
+	Credential json.RawMessage `json:"credential"`
+}
+
+func (r *FinishWebauthnLogin) Validate() error {
+	validationErrDetails := map[string]any{}
+	validateField(r.SessionID, "session_id", validationErrDetails, validation.Required)
+	validateField(string(r.Credential), "credential", validationErrDetails, validation.Required)
+	return buildValidationError(validationErrDetails)
+}
+
+type DeleteWebauthnCredential struct {
+	ID     uint
+	UserID uint
+}
@@ -0,0 +1,8 @@
+package request
+
+type GetUserIdentity struct {
+	UserID   uint
+	Provider string
+	Subject  string
+	Preloads []string
+}
@@ -0,0 +1,8 @@
+package request
+
+type GetUserMfaFactor struct {
+	UserID      uint
+	Type        string
+	IsConfirmed *bool
+	Preloads    []string
+}
@@ -0,0 +1,6 @@
+package request
+
+type GetUserPasswordHistories struct {
+	UserID uint
+	Limit  int
+}
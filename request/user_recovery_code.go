@@ -0,0 +1,7 @@
+package request
+
+type GetUserRecoveryCodes struct {
+	UserID   uint
+	IsUsed   *bool
+	Preloads []string
+}
@@ -0,0 +1,41 @@
+package request
+
+import (
+	"app/model"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type GetUserTransfer struct {
+	ID string
+}
+
+type ImportUsers struct {
+	FileData
+	UserID uint
+}
+
+type ExportUsers struct {
+	GetUsers
+	Format string `json:"format"`
+	UserID uint
+}
+
+func (r *ExportUsers) Validate() error {
+	validationErrDetails := map[string]any{}
+	validateField(r.Format, "format", validationErrDetails, validation.Required, validation.In(model.UserTransferFormatCsv, model.UserTransferFormatXlsx))
+	return buildValidationError(validationErrDetails)
+}
+
+type ImportUsersPayload struct {
+	Format     string `json:"format"`
+	SourcePath string `json:"source_path"`
+	UserID     uint   `json:"user_id"` // The user who started the import, recorded as the actor of the created users
+}
+
+type ExportUsersPayload struct {
+	Format  string   `json:"format"`
+	Search  string   `json:"search"`
+	Sort    []string `json:"sort"`
+	Filters []Filter `json:"filters"`
+}
@@ -1,9 +1,10 @@
 package request
 
 type GetUserVerification struct {
-	Type     string
-	UserID   uint
-	Code     string
-	IsUsed   *bool
-	Preloads []string
+	Type      string
+	UserID    uint
+	Code      string
+	IsUsed    *bool
+	IsExpired *bool
+	Preloads  []string
 }
@@ -6,3 +6,13 @@ type SendEmailPayload struct {
 	TemplateData map[string]any `json:"template_data"`
 	Subject      string         `json:"subject"`
 }
+
+type SendSmsPayload struct {
+	To      string `json:"to"`
+	Channel string `json:"channel"`
+	Message string `json:"message"`
+}
+
+type EraseUserPayload struct {
+	UserID uint `json:"user_id"`
+}
@@ -0,0 +1,47 @@
+package response
+
+import (
+	"app/model"
+	"time"
+)
+
+type GetApiClients struct {
+	BasePaginateResponse
+	Data []ApiClient `json:"data"`
+}
+
+type ApiClient struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	ClientID  string    `json:"client_id"`
+	Scopes    []string  `json:"scopes"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewApiClient(apiClient model.ApiClient) ApiClient {
+	return ApiClient{
+		ID:        apiClient.ID,
+		Name:      apiClient.Name,
+		ClientID:  apiClient.ClientID,
+		Scopes:    apiClient.GetScopes(),
+		IsActive:  apiClient.IsActive,
+		CreatedAt: apiClient.CreatedAt,
+		UpdatedAt: apiClient.UpdatedAt,
+	}
+}
+
+// CreateApiClient is the only response containing the client secret, it is not retrievable afterwards
+type CreateApiClient struct {
+	ApiClient
+	ClientSecret string `json:"client_secret"`
+}
+
+// OauthToken follow https://datatracker.ietf.org/doc/html/rfc6749#section-5.1
+type OauthToken struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	Scope       string `json:"scope"`
+}
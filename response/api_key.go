@@ -0,0 +1,34 @@
+package response
+
+import (
+	"app/model"
+	"time"
+)
+
+type ApiKey struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	KeyPrefix  string     `json:"key_prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiredAt  *time.Time `json:"expired_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func NewApiKey(userApiKey model.UserApiKey) ApiKey {
+	return ApiKey{
+		ID:         userApiKey.ID,
+		Name:       userApiKey.Name,
+		KeyPrefix:  userApiKey.KeyPrefix,
+		Scopes:     userApiKey.GetScopes(),
+		ExpiredAt:  userApiKey.ExpiredAt,
+		LastUsedAt: userApiKey.LastUsedAt,
+		CreatedAt:  userApiKey.CreatedAt,
+	}
+}
+
+// CreateApiKey is the only response containing the plain key, only its hash is stored
+type CreateApiKey struct {
+	ApiKey
+	Key string `json:"key"`
+}
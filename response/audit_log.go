@@ -0,0 +1,43 @@
+package response
+
+import (
+	"app/model"
+	"encoding/json"
+	"time"
+)
+
+type GetAuditLogs struct {
+	BasePaginateResponse
+	Data []AuditLog `json:"data"`
+}
+
+type AuditLog struct {
+	ID         uint            `json:"id"`
+	ActorID    uint            `json:"actor_id"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   uint            `json:"target_id"`
+	Changes    json.RawMessage `json:"changes"`
+	IPAddress  string          `json:"ip_address"`
+	UserAgent  string          `json:"user_agent"`
+	RequestID  string          `json:"request_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+func NewAuditLog(auditLog model.AuditLog) AuditLog {
+	res := AuditLog{
+		ID:         auditLog.ID,
+		ActorID:    auditLog.ActorID,
+		Action:     auditLog.Action,
+		TargetType: auditLog.TargetType,
+		TargetID:   auditLog.TargetID,
+		IPAddress:  auditLog.IPAddress,
+		UserAgent:  auditLog.UserAgent,
+		RequestID:  auditLog.RequestID,
+		CreatedAt:  auditLog.CreatedAt,
+	}
+	if auditLog.Changes != "" {
+		res.Changes = json.RawMessage(auditLog.Changes)
+	}
+	return res
+}
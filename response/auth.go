@@ -15,6 +15,7 @@ type Auth struct {
 	RefreshTokenExpiredAt time.Time    `json:"refresh_token_expired_at"`
 	CreatedAt             time.Time    `json:"created_at"`
 	UpdatedAt             time.Time    `json:"updated_at"`
+	RecoveryCodes         []string     `json:"recovery_codes,omitempty"` // Only set when the first validated otp issue the recovery codes
 }
 
 func NewAuth(auth model.UserAuth, user model.User, isNeedMfa bool) Auth {
@@ -30,3 +31,25 @@ func NewAuth(auth model.UserAuth, user model.User, isNeedMfa bool) Auth {
 		UpdatedAt:             auth.UpdatedAt,
 	}
 }
+
+type Session struct {
+	ID         uint       `json:"id"`
+	UserAgent  string     `json:"user_agent"`
+	IPAddress  string     `json:"ip_address"`
+	IsCurrent  bool       `json:"is_current"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiredAt  time.Time  `json:"expired_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func NewSession(auth model.UserAuth, isCurrent bool) Session {
+	return Session{
+		ID:         auth.ID,
+		UserAgent:  auth.UserAgent,
+		IPAddress:  auth.IPAddress,
+		IsCurrent:  isCurrent,
+		LastUsedAt: auth.LastUsedAt,
+		ExpiredAt:  auth.RefreshTokenExpiredAt,
+		CreatedAt:  auth.CreatedAt,
+	}
+}
@@ -0,0 +1,38 @@
+package response
+
+import (
+	"app/model"
+	"encoding/json"
+	"time"
+)
+
+type Job struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	State      string          `json:"state"`
+	Progress   int             `json:"progress"`
+	Result     json.RawMessage `json:"result"`
+	Error      string          `json:"error"`
+	StartedAt  *time.Time      `json:"started_at"`
+	FinishedAt *time.Time      `json:"finished_at"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+func NewJob(job model.Job) Job {
+	res := Job{
+		ID:         job.ID,
+		Type:       job.Type,
+		State:      job.State,
+		Progress:   job.Progress,
+		Error:      job.Error,
+		StartedAt:  job.StartedAt,
+		FinishedAt: job.FinishedAt,
+		CreatedAt:  job.CreatedAt,
+		UpdatedAt:  job.UpdatedAt,
+	}
+	if job.Result != "" {
+		res.Result = json.RawMessage(job.Result)
+	}
+	return res
+}
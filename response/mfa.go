@@ -0,0 +1,12 @@
+package response
+
+type EnrollTotp struct {
+	Secret    string `json:"secret"`
+	URI       string `json:"uri"`
+	QrCodePng string `json:"qr_code_png"` // Base64 encoded PNG
+}
+
+// RecoveryCodes is only shown once after generated, only the hashes are stored
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -1,8 +1,12 @@
 package response
 
 type BasePaginateResponse struct {
-	Total  uint `json:"-"`
-	Offset uint `json:"-"`
-	Page   uint `json:"-"`
-	Limit  uint `json:"-"`
+	Total      uint   `json:"-"`
+	Offset     uint   `json:"-"`
+	Page       uint   `json:"-"`
+	Limit      uint   `json:"-"`
+	IsCursor   bool   `json:"-"`
+	HasTotal   bool   `json:"-"` // Only used in cursor pagination, the total is always counted in page pagination
+	NextCursor string `json:"-"`
+	PrevCursor string `json:"-"`
 }
@@ -53,3 +53,31 @@ func NewUserDetailed(user model.User) UserDetailed {
 		UpdatedAt:   user.UpdatedAt,
 	}
 }
+
+type Profile struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Email       string    `json:"email"`
+	PhoneNumber string    `json:"phone_number"`
+	AvatarURL   string    `json:"avatar_url"`
+	IsVerified  bool      `json:"is_verified"`
+	Roles       []string  `json:"roles"`
+	Permissions []string  `json:"permissions"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func NewProfile(user model.User, avatarURL string, roles, permissions []string) Profile {
+	return Profile{
+		ID:          user.ID,
+		Name:        user.Name,
+		Email:       user.Email,
+		PhoneNumber: user.PhoneNumber,
+		AvatarURL:   avatarURL,
+		IsVerified:  user.IsVerified,
+		Roles:       roles,
+		Permissions: permissions,
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
+	}
+}
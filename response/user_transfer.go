@@ -0,0 +1,38 @@
+package response
+
+import (
+	"app/model"
+	"time"
+)
+
+type UserTransfer struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	State     string    `json:"state"`
+	Format    string    `json:"format"`
+	Total     int       `json:"total"`
+	Processed int       `json:"processed"`
+	Failed    int       `json:"failed"`
+	Progress  int       `json:"progress"`
+	ResultURL string    `json:"result_url"` // The exported file or the import error report
+	Error     string    `json:"error"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewUserTransfer(job model.Job, userTransfer model.UserTransfer, resultURL string) UserTransfer {
+	return UserTransfer{
+		ID:        job.ID,
+		Type:      userTransfer.Type,
+		State:     job.State,
+		Format:    userTransfer.Format,
+		Total:     userTransfer.Total,
+		Processed: userTransfer.Processed,
+		Failed:    userTransfer.Failed,
+		Progress:  job.Progress,
+		ResultURL: resultURL,
+		Error:     job.Error,
+		CreatedAt: job.CreatedAt,
+		UpdatedAt: job.UpdatedAt,
+	}
+}
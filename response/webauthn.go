@@ -0,0 +1,36 @@
+package response
+
+import (
+	"app/model"
+	"time"
+)
+
+// WebauthnCeremony options are passed as is to navigator.credentials.create() or navigator.credentials.get(),
+// the session id must be sent back together with the authenticator response to finish the ceremony
+type WebauthnCeremony struct {
+	SessionID string `json:"session_id"`
+	Options   any    `json:"options"`
+}
+
+type WebauthnCredential struct {
+	ID             uint       `json:"id"`
+	Name           string     `json:"name"`
+	Transports     []string   `json:"transports"`
+	BackupEligible bool       `json:"backup_eligible"`
+	BackupState    bool       `json:"backup_state"`
+	LastUsedAt     *time.Time `json:"last_used_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	RecoveryCodes  []string   `json:"recovery_codes,omitempty"` // Only set when the first passkey issue the recovery codes
+}
+
+func NewWebauthnCredential(userCredential model.UserCredential) WebauthnCredential {
+	return WebauthnCredential{
+		ID:             userCredential.ID,
+		Name:           userCredential.Name,
+		Transports:     userCredential.GetTransports(),
+		BackupEligible: userCredential.BackupEligible,
+		BackupState:    userCredential.BackupState,
+		LastUsedAt:     userCredential.LastUsedAt,
+		CreatedAt:      userCredential.CreatedAt,
+	}
+}
@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"app/lib"
+	"app/lib/auth"
+	"app/lib/constant"
+	"app/lib/logger"
+	"app/lib/signoz"
+	"app/model"
+	"app/request"
+	"app/response"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+func (usecase *Usecase) GetApiClients(ctx context.Context, req request.GetApiClients) (res response.GetApiClients, err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.GetApiClients")
+	defer span.Finish()
+
+	apiClients, total, err := usecase.repo.GetApiClients(ctx, req)
+	if err != nil {
+		return res, err
+	}
+
+	res.Data = []response.ApiClient{}
+	for _, apiClient := range apiClients {
+		res.Data = append(res.Data, response.NewApiClient(apiClient))
+	}
+	res.Total = uint(total)
+	return res, nil
+}
+
+// CreateApiClient register a machine to machine client, the generated secret is only returned once.
+// The scopes can not exceed the permissions and scopes of the caller
+func (usecase *Usecase) CreateApiClient(ctx context.Context, req request.CreateApiClient) (res response.CreateApiClient, err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.CreateApiClient")
+	defer span.Finish()
+
+	for _, scope := range req.Scopes {
+		if !slices.Contains(req.GrantedScopes, scope) {
+			validationError := lib.ErrorValidation
+			validationError.ErrDetails = map[string]any{
+				"scopes": "scope " + scope + " is not granted to the caller",
+			}
+			return res, validationError
+		}
+	}
+
+	clientSecret, err := auth.GenerateSecret(ctx, constant.ApiClientSecretLength)
+	if err != nil {
+		return res, err
+	}
+
+	clientSecretHash, err := lib.GeneratePasswordHash(clientSecret, usecase.config.BCRYPT_COST)
+	if err != nil {
+		logger.LogError(ctx, "Error GeneratePasswordHash", []zap.Field{
+			zap.Error(err),
+			zap.Strings("tags", []string{"usecase", "CreateApiClient"}),
+		}...)
+		return res, lib.ErrorInternalServer
+	}
+
+	var apiClient model.ApiClient
+	err = usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		timeNow := time.Now()
+		var err error
+		apiClient, err = usecase.repo.CreateApiClient(ctx, model.ApiClient{
+			Name:             req.Name,
+			ClientID:         constant.ApiClientIDPrefix + strings.ReplaceAll(lib.GenerateUUID(), "-", ""),
+			ClientSecretHash: clientSecretHash,
+			Scopes:           strings.Join(req.Scopes, " "),
+			IsActive:         true,
+			CreatedAt:        timeNow,
+			UpdatedAt:        timeNow,
+		})
+		if err != nil {
+			return err
+		}
+
+		return usecase.recordAuditLog(ctx, model.AuditLog{
+			Action:     model.AuditLogActionApiClientCreate,
+			TargetType: model.AuditLogTargetApiClient,
+			TargetID:   apiClient.ID,
+		}, nil, nil)
+	})
+	if err != nil {
+		return res, err
+	}
+
+	res.ApiClient = response.NewApiClient(apiClient)
+	res.ClientSecret = clientSecret
+	return res, nil
+}
+
+func (usecase *Usecase) DeleteApiClient(ctx context.Context, req request.DeleteApiClient) (err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.DeleteApiClient")
+	defer span.Finish()
+
+	apiClient, err := usecase.repo.GetApiClient(ctx, request.GetApiClient{
+		ID: req.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if apiClient.ID == 0 {
+		notFoundError := lib.ErrorNotFound
+		notFoundError.Message = "API Client Not Found"
+		return notFoundError
+	}
+
+	return usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := usecase.repo.DeleteApiClient(ctx, apiClient)
+		if err != nil {
+			return err
+		}
+
+		return usecase.recordAuditLog(ctx, model.AuditLog{
+			Action:     model.AuditLogActionApiClientDelete,
+			TargetType: model.AuditLogTargetApiClient,
+			TargetID:   apiClient.ID,
+		}, nil, nil)
+	})
+}
+
+// ValidateApiClient reject the access token of an api client deleted or deactivated after the token was issued
+func (usecase *Usecase) ValidateApiClient(ctx context.Context, clientId string) error {
+	ctx, span := signoz.StartSpan(ctx, "usecase.ValidateApiClient")
+	defer span.Finish()
+
+	isActive := true
+	apiClient, err := usecase.repo.GetApiClient(ctx, request.GetApiClient{
+		ClientID: clientId,
+		IsActive: &isActive,
+	})
+	if err != nil {
+		return err
+	}
+	if apiClient.ID == 0 {
+		return lib.ErrorUnauthorized
+	}
+
+	return nil
+}
+
+// IssueOauthToken issue an opaque access token through the oauth2 client credentials grant,
+// the token is stored the same way as the user access token so AuthMiddleware accept both
+func (usecase *Usecase) IssueOauthToken(ctx context.Context, req request.OauthToken) (res response.OauthToken, err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.IssueOauthToken")
+	defer span.Finish()
+
+	isActive := true
+	apiClient, err := usecase.repo.GetApiClient(ctx, request.GetApiClient{
+		ClientID: req.ClientID,
+		IsActive: &isActive,
+	})
+	if err != nil {
+		return res, err
+	}
+	if apiClient.ID == 0 {
+		return res, lib.ErrorUnauthorized
+	}
+
+	err = lib.CompareHashAndPassword(apiClient.ClientSecretHash, req.ClientSecret)
+	if err != nil {
+		return res, lib.ErrorUnauthorized
+	}
+
+	// Requested scopes must be a subset of the client scopes, no scope means every client scope
+	scopes := apiClient.GetScopes()
+	if req.Scope != "" {
+		scopes = strings.Fields(req.Scope)
+		for _, scope := range scopes {
+			if !slices.Contains(apiClient.GetScopes(), scope) {
+				validationError := lib.ErrorValidation
+				validationError.ErrDetails = map[string]any{
+					"scope": "scope is not allowed for this client",
+				}
+				return res, validationError
+			}
+		}
+	}
+
+	timeNow := time.Now()
+	tokenExp := timeNow.Add(time.Duration(usecase.config.OAUTH_ACCESS_TOKEN_TTL) * time.Second)
+	idToken, err := usecase.generateIDToken(ctx, auth.IDTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(tokenExp),
+			IssuedAt:  jwt.NewNumericDate(timeNow),
+			NotBefore: jwt.NewNumericDate(timeNow),
+			Issuer:    constant.DefaultIssuer,
+			Subject:   apiClient.ClientID,
+			Audience:  []string{constant.DefaultAudience},
+		},
+		ClientID: apiClient.ClientID,
+		Scopes:   scopes,
+	})
+	if err != nil {
+		return res, err
+	}
+
+	accessToken, err := usecase.generateAccessToken(ctx, auth.AccessTokenClaims{
+		Sub:     apiClient.ClientID,
+		Exp:     uint(tokenExp.Unix()),
+		IDToken: idToken,
+	})
+	if err != nil {
+		return res, err
+	}
+
+	return response.OauthToken{
+		AccessToken: accessToken,
+		TokenType:   constant.TokenTypeBearer,
+		ExpiresIn:   usecase.config.OAUTH_ACCESS_TOKEN_TTL,
+		Scope:       strings.Join(scopes, " "),
+	}, nil
+}
@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"app/lib"
+	"app/lib/auth"
+	"app/lib/constant"
+	"app/lib/logger"
+	"app/lib/signoz"
+	"app/model"
+	"app/request"
+	"app/response"
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+func (usecase *Usecase) GetApiKeys(ctx context.Context, req request.GetUserApiKeys) (res []response.ApiKey, err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.GetApiKeys")
+	defer span.Finish()
+
+	userApiKeys, err := usecase.repo.GetUserApiKeys(ctx, req)
+	if err != nil {
+		return res, err
+	}
+
+	res = []response.ApiKey{}
+	for _, userApiKey := range userApiKeys {
+		res = append(res, response.NewApiKey(userApiKey))
+	}
+	return res, nil
+}
+
+// CreateApiKey create a personal api key, the scopes can not exceed the permissions the user currently has
+func (usecase *Usecase) CreateApiKey(ctx context.Context, req request.CreateApiKey) (res response.CreateApiKey, err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.CreateApiKey")
+	defer span.Finish()
+
+	_, permissions, err := usecase.getUserRolesAndPermissions(ctx, req.UserID)
+	if err != nil {
+		return res, err
+	}
+	for _, scope := range req.Scopes {
+		if !slices.Contains(permissions, scope) {
+			validationError := lib.ErrorValidation
+			validationError.ErrDetails = map[string]any{
+				"scopes": "scope " + scope + " is not granted to the user",
+			}
+			return res, validationError
+		}
+	}
+
+	secret, err := auth.GenerateSecret(ctx, constant.ApiKeyLength)
+	if err != nil {
+		return res, err
+	}
+	apiKey := constant.ApiKeyPrefix + secret
+
+	var userApiKey model.UserApiKey
+	err = usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		timeNow := time.Now()
+		var err error
+		userApiKey, err = usecase.repo.CreateUserApiKey(ctx, model.UserApiKey{
+			UserID:    req.UserID,
+			Name:      req.Name,
+			KeyPrefix: apiKey[:len(constant.ApiKeyPrefix)+constant.ApiKeyDisplayLength],
+			KeyHash:   auth.HashApiKey(apiKey),
+			Scopes:    strings.Join(req.Scopes, " "),
+			ExpiredAt: req.ExpiredAt,
+			CreatedAt: timeNow,
+			UpdatedAt: timeNow,
+		})
+		if err != nil {
+			return err
+		}
+
+		return usecase.recordAuditLog(ctx, model.AuditLog{
+			Action:     model.AuditLogActionApiKeyCreate,
+			TargetType: model.AuditLogTargetUser,
+			TargetID:   req.UserID,
+		}, nil, nil)
+	})
+	if err != nil {
+		return res, err
+	}
+
+	res.ApiKey = response.NewApiKey(userApiKey)
+	res.Key = apiKey
+	return res, nil
+}
+
+func (usecase *Usecase) RevokeApiKey(ctx context.Context, req request.RevokeApiKey) error {
+	ctx, span := signoz.StartSpan(ctx, "usecase.RevokeApiKey")
+	defer span.Finish()
+
+	userApiKey, err := usecase.repo.GetUserApiKey(ctx, request.GetUserApiKey{
+		ID:     req.ID,
+		UserID: req.UserID,
+	})
+	if err != nil {
+		return err
+	}
+	if userApiKey.ID == 0 {
+		notFoundError := lib.ErrorNotFound
+		notFoundError.Message = "API Key Not Found"
+		return notFoundError
+	}
+
+	return usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := usecase.repo.DeleteUserApiKey(ctx, userApiKey)
+		if err != nil {
+			return err
+		}
+
+		return usecase.recordAuditLog(ctx, model.AuditLog{
+			Action:     model.AuditLogActionApiKeyRevoke,
+			TargetType: model.AuditLogTargetUser,
+			TargetID:   userApiKey.UserID,
+		}, nil, nil)
+	})
+}
+
+// AuthenticateApiKey resolve the api key into id token claims, so the rest of the request flow treat it like an access token.
+// The permissions are the intersection of the key scopes and the user current permissions, so revoking a role also
+// restrict the keys created while the user had it
+func (usecase *Usecase) AuthenticateApiKey(ctx context.Context, apiKey string) (*auth.IDTokenClaims, error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.AuthenticateApiKey")
+	defer span.Finish()
+
+	userApiKey, err := usecase.repo.GetUserApiKey(ctx, request.GetUserApiKey{
+		KeyHash: auth.HashApiKey(apiKey),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if userApiKey.ID == 0 || userApiKey.IsExpired() {
+		return nil, lib.ErrorUnauthorized
+	}
+
+	user, err := usecase.repo.GetUser(ctx, request.GetUser{
+		ID: userApiKey.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if user.ID == 0 || !user.IsActive {
+		return nil, lib.ErrorUnauthorized
+	}
+
+	roles, permissions, err := usecase.getUserRolesAndPermissions(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := []string{}
+	for _, scope := range userApiKey.GetScopes() {
+		if slices.Contains(permissions, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	timeNow := time.Now()
+	threshold := timeNow.Add(-constant.SessionLastUsedInterval * time.Second)
+	err = usecase.repo.UpdateUserApiKeyLastUsedAt(ctx, userApiKey.ID, timeNow, threshold)
+	if err != nil {
+		logger.LogError(ctx, "Error UpdateUserApiKeyLastUsedAt", []zap.Field{
+			zap.Error(err),
+			zap.Strings("tags", []string{"usecase", "AuthenticateApiKey"}),
+		}...)
+	}
+
+	idTokenClaims := &auth.IDTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt: jwt.NewNumericDate(timeNow),
+			Issuer:   constant.DefaultIssuer,
+			Subject:  strconv.Itoa(int(user.ID)),
+			Audience: []string{constant.DefaultAudience},
+		},
+		UserID:      user.ID,
+		Roles:       roles,
+		Permissions: scopes,
+		Scopes:      scopes,
+		IsApiKey:    true,
+	}
+	if userApiKey.ExpiredAt != nil {
+		idTokenClaims.ExpiresAt = jwt.NewNumericDate(*userApiKey.ExpiredAt)
+	}
+
+	return idTokenClaims, nil
+}
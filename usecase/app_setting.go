@@ -54,3 +54,22 @@ func (usecase *Usecase) getAppSettingString(ctx context.Context, slug string) (s
 	}
 	return appSetting.Value, nil
 }
+
+// getAppSettingBool get data with bool data type from app_settings table
+func (usecase *Usecase) getAppSettingBool(ctx context.Context, slug string) (bool, error) {
+	appSetting, err := usecase.repo.GetAppSettingBySlug(ctx, slug)
+	if err != nil {
+		return false, err
+	}
+
+	value, err := strconv.ParseBool(appSetting.Value)
+	if err != nil {
+		logger.LogError(ctx, "Error getAppSettingBool", []zap.Field{
+			zap.Error(err),
+			zap.Strings("tags", []string{"app_setting", "slug", slug}),
+		}...)
+		return false, err
+	}
+
+	return value, nil
+}
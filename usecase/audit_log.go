@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"app/lib/auth"
+	"app/lib/logger"
+	"app/lib/signoz"
+	"app/model"
+	"app/request"
+	"app/response"
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// auditLogFields are the only fields written into the changes, so a secret or a new field is never logged by default
+var auditLogFields = map[string]bool{
+	"name":                true,
+	"email":               true,
+	"phone_number":        true,
+	"avatar_path":         true,
+	"is_active":           true,
+	"is_verified":         true,
+	"password_changed_at": true,
+	"roles":               true,
+	"deleted_at":          true,
+}
+
+type auditLogChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+func (usecase *Usecase) GetAuditLogs(ctx context.Context, req request.GetAuditLogs) (res response.GetAuditLogs, err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.GetAuditLogs")
+	defer span.Finish()
+
+	auditLogs, total, err := usecase.repo.GetAuditLogs(ctx, req)
+	if err != nil {
+		return res, err
+	}
+
+	res.Data = []response.AuditLog{}
+	for _, auditLog := range auditLogs {
+		res.Data = append(res.Data, response.NewAuditLog(auditLog))
+	}
+	res.Total = uint(total)
+	return res, nil
+}
+
+// recordAuditLog write the audit log of an action, with the changed fields between before and after when both are given.
+// The actor default to the authenticated user, and the client and request id are taken from ctx.
+// Call it inside the transaction of the action so the log is only kept when the action is committed
+func (usecase *Usecase) recordAuditLog(ctx context.Context, auditLog model.AuditLog, before, after any) error {
+	if auditLog.ActorID == 0 {
+		if idTokenClaims := auth.GetAuthFromCtx(ctx); idTokenClaims != nil {
+			auditLog.ActorID = idTokenClaims.UserID
+		}
+	}
+
+	if before != nil && after != nil {
+		changes, err := diffAuditLogFields(before, after)
+		if err != nil {
+			return err
+		}
+		auditLog.Changes = changes
+	}
+
+	device := auth.GetDeviceFromCtx(ctx)
+	auditLog.IPAddress = device.IPAddress
+	auditLog.UserAgent = device.UserAgent
+	auditLog.RequestID, _ = ctx.Value(logger.CtxRequestID).(string)
+	auditLog.CreatedAt = time.Now()
+
+	_, err := usecase.repo.CreateAuditLog(ctx, auditLog)
+	return err
+}
+
+// diffAuditLogFields compare the json fields of before and after, an empty string is returned when nothing changed
+func diffAuditLogFields(before, after any) (string, error) {
+	beforeFields, err := toAuditLogFields(before)
+	if err != nil {
+		return "", err
+	}
+	afterFields, err := toAuditLogFields(after)
+	if err != nil {
+		return "", err
+	}
+
+	changes := map[string]auditLogChange{}
+	for field, afterValue := range afterFields {
+		if !auditLogFields[field] {
+			continue
+		}
+		beforeValue := beforeFields[field]
+		if !reflect.DeepEqual(beforeValue, afterValue) {
+			changes[field] = auditLogChange{Before: beforeValue, After: afterValue}
+		}
+	}
+	if len(changes) == 0 {
+		return "", nil
+	}
+
+	changesBytes, err := json.Marshal(changes)
+	if err != nil {
+		return "", err
+	}
+	return string(changesBytes), nil
+}
+
+func toAuditLogFields(value any) (map[string]any, error) {
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]any{}
+	err = json.Unmarshal(valueBytes, &fields)
+	if err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDiffAuditLogFields(t *testing.T) {
+	type user struct {
+		Name              string   `json:"name"`
+		Email             string   `json:"email"`
+		EncryptedPassword string   `json:"encrypted_password"`
+		IsActive          bool     `json:"is_active"`
+		Roles             []string `json:"roles"`
+	}
+
+	base := user{Name: "John", Email: "john@example.com", EncryptedPassword: "hash", IsActive: true, Roles: []string{"USER"}}
+
+	tests := []struct {
+		name   string
+		before any
+		after  any
+		want   map[string]auditLogChange
+	}{
+		{name: "nothing changed", before: base, after: base, want: nil},
+		{
+			name:   "changed fields",
+			before: base,
+			after:  user{Name: "Jane", Email: base.Email, EncryptedPassword: base.EncryptedPassword, IsActive: false, Roles: []string{"USER", "ADMIN"}},
+			want: map[string]auditLogChange{
+				"name":      {Before: "John", After: "Jane"},
+				"is_active": {Before: true, After: false},
+				"roles":     {Before: []any{"USER"}, After: []any{"USER", "ADMIN"}},
+			},
+		},
+		{
+			name:   "field outside the allowlist is skipped",
+			before: base,
+			after:  user{Name: base.Name, Email: base.Email, EncryptedPassword: "new hash", IsActive: base.IsActive, Roles: base.Roles},
+			want:   nil,
+		},
+		{
+			name:   "created",
+			before: nil,
+			after:  base,
+			want: map[string]auditLogChange{
+				"name":      {Before: nil, After: "John"},
+				"email":     {Before: nil, After: "john@example.com"},
+				"is_active": {Before: nil, After: true},
+				"roles":     {Before: nil, After: []any{"USER"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := diffAuditLogFields(tt.before, tt.after)
+			if err != nil {
+				t.Fatalf("err = %v, want nil", err)
+			}
+			if tt.want == nil {
+				if got != "" {
+					t.Errorf("changes = %s, want empty", got)
+				}
+				return
+			}
+
+			changes := map[string]auditLogChange{}
+			if err := json.Unmarshal([]byte(got), &changes); err != nil {
+				t.Fatalf("unmarshal changes: %v", err)
+			}
+			if !reflect.DeepEqual(changes, tt.want) {
+				t.Errorf("changes = %#v, want %#v", changes, tt.want)
+			}
+		})
+	}
+}
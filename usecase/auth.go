@@ -5,19 +5,21 @@ import (
 	"app/lib/auth"
 	"app/lib/constant"
 	"app/lib/logger"
+	"app/lib/oidc"
 	"app/lib/signoz"
 	"app/model"
 	"app/request"
 	"app/response"
 	"context"
 	"errors"
+	"fmt"
+	"net/netip"
 	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
-	"google.golang.org/api/idtoken"
 )
 
 func (usecase *Usecase) Register(ctx context.Context, req request.Register) (err error) {
@@ -38,13 +40,14 @@ func (usecase *Usecase) Register(ctx context.Context, req request.Register) (err
 		return validationError
 	}
 
-	encryptedPassword, err := lib.GeneratePasswordHash(req.Password)
+	err = usecase.validatePassword(ctx, "password", req.Password, model.User{})
 	if err != nil {
-		logger.LogError(ctx, "Error GeneratePasswordHash", []zap.Field{
-			zap.Error(err),
-			zap.Strings("tags", []string{"usecase", "Register"}),
-		}...)
-		return lib.ErrorInternalServer
+		return err
+	}
+
+	encryptedPassword, err := usecase.generatePasswordHash(ctx, req.Password)
+	if err != nil {
+		return err
 	}
 
 	return usecase.repo.Transaction(ctx, func(ctx context.Context) error {
@@ -56,6 +59,7 @@ func (usecase *Usecase) Register(ctx context.Context, req request.Register) (err
 			EncryptedPassword: encryptedPassword,
 			IsActive:          true,
 			IsVerified:        false,
+			PasswordChangedAt: &timeNow,
 			CreatedAt:         timeNow,
 			UpdatedAt:         timeNow,
 		})
@@ -63,12 +67,19 @@ func (usecase *Usecase) Register(ctx context.Context, req request.Register) (err
 			return err
 		}
 
-		userVerification, err := usecase.repo.CreateUserVerification(ctx, model.UserVerification{
-			Type:      model.UserVerificationTypeVerifyAccount,
-			UserID:    user.ID,
-			Code:      lib.GenerateUUID(),
-			CreatedAt: timeNow,
-			UpdatedAt: timeNow,
+		err = usecase.recordPasswordHistory(ctx, user)
+		if err != nil {
+			return err
+		}
+
+		err = usecase.assignDefaultRole(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+
+		userVerification, err := usecase.createUserVerification(ctx, model.UserVerification{
+			Type:   model.UserVerificationTypeVerifyAccount,
+			UserID: user.ID,
 		})
 		if err != nil {
 			return err
@@ -118,22 +129,29 @@ func (usecase *Usecase) RegisterResendVerification(ctx context.Context, req requ
 		return verificationDelayError
 	}
 
-	isUsed := false
-	userVerification, err := usecase.repo.GetUserVerification(ctx, request.GetUserVerification{
-		Type:   model.UserVerificationTypeVerifyAccount,
-		UserID: user.ID,
-		IsUsed: &isUsed,
-	})
-	if err != nil {
-		return err
-	}
-	if userVerification.ID == 0 {
+	if user.IsVerified {
 		notFoundError := lib.ErrorNotFound
 		notFoundError.Message = "User Verification Not Found"
 		return notFoundError
 	}
 
+	userVerification, err := usecase.getActiveUserVerification(ctx, user.ID, model.UserVerificationTypeVerifyAccount)
+	if err != nil {
+		return err
+	}
+
 	return usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		// The previous code has expired, a new one is issued instead
+		if userVerification.ID == 0 {
+			userVerification, err = usecase.createUserVerification(ctx, model.UserVerification{
+				Type:   model.UserVerificationTypeVerifyAccount,
+				UserID: user.ID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
 		err = usecase.repo.PublishTask(ctx, constant.TaskTypeEmailSend, request.SendEmailPayload{
 			To:           []string{user.Email},
 			TemplateName: "register_verification.html",
@@ -155,6 +173,7 @@ func (usecase *Usecase) VerifyAccount(ctx context.Context, req request.VerifyAcc
 	defer span.Finish()
 
 	userVerification, err := usecase.repo.GetUserVerification(ctx, request.GetUserVerification{
+		Type: model.UserVerificationTypeVerifyAccount,
 		Code: req.Code,
 	})
 	if err != nil {
@@ -166,12 +185,9 @@ func (usecase *Usecase) VerifyAccount(ctx context.Context, req request.VerifyAcc
 		return response.Auth{}, notFoundError
 	}
 
-	if userVerification.ExpiredAt != nil && !userVerification.ExpiredAt.IsZero() {
-		return response.Auth{}, lib.ErrorVerificationInactive
-	}
-
-	if userVerification.UsedAt != nil && !userVerification.UsedAt.IsZero() {
-		return response.Auth{}, lib.ErrorVerificationInactive
+	err = checkUserVerification(userVerification)
+	if err != nil {
+		return response.Auth{}, err
 	}
 
 	user, err := usecase.repo.GetUser(ctx, request.GetUser{
@@ -190,7 +206,6 @@ func (usecase *Usecase) VerifyAccount(ctx context.Context, req request.VerifyAcc
 	err = usecase.repo.Transaction(ctx, func(ctx context.Context) error {
 		timeNow := time.Now()
 
-		user.IsActive = true
 		user.IsVerified = true
 		user.UpdatedAt = timeNow
 		_, err := usecase.repo.UpdateUser(ctx, user)
@@ -198,10 +213,7 @@ func (usecase *Usecase) VerifyAccount(ctx context.Context, req request.VerifyAcc
 			return err
 		}
 
-		userVerification.ExpiredAt = &timeNow
-		userVerification.UsedAt = &timeNow
-		userVerification.UpdatedAt = timeNow
-		_, err = usecase.repo.UpdateUserVerification(ctx, userVerification)
+		err = usecase.useUserVerification(ctx, userVerification, timeNow)
 		if err != nil {
 			return err
 		}
@@ -225,6 +237,11 @@ func (usecase *Usecase) Login(ctx context.Context, req request.Login) (res respo
 	ctx, span := signoz.StartSpan(ctx, "usecase.Login")
 	defer span.Finish()
 
+	err = usecase.checkLoginLockout(ctx, req.Email)
+	if err != nil {
+		return res, err
+	}
+
 	user, err := usecase.repo.GetUser(ctx, request.GetUser{
 		Email: req.Email,
 	})
@@ -232,6 +249,11 @@ func (usecase *Usecase) Login(ctx context.Context, req request.Login) (res respo
 		return res, err
 	}
 	if user.ID == 0 {
+		err = usecase.recordLoginFailure(ctx, user, req.Email)
+		if err != nil {
+			return res, err
+		}
+
 		notFoundError := lib.ErrorNotFound
 		notFoundError.Message = "User Not Found"
 		return res, notFoundError
@@ -239,15 +261,58 @@ func (usecase *Usecase) Login(ctx context.Context, req request.Login) (res respo
 
 	err = lib.CompareHashAndPassword(user.EncryptedPassword, req.Password)
 	if err != nil {
+		err = usecase.recordLoginFailure(ctx, user, req.Email)
+		if err != nil {
+			return res, err
+		}
+
+		err = usecase.recordAuditLog(ctx, model.AuditLog{
+			Action:     model.AuditLogActionLoginFailed,
+			TargetType: model.AuditLogTargetUser,
+			TargetID:   user.ID,
+		}, nil, nil)
+		if err != nil {
+			return res, err
+		}
 		return res, lib.ErrorWrongCredential
 	}
 
+	err = usecase.resetLoginFailure(ctx, req.Email)
+	if err != nil {
+		return res, err
+	}
+
+	err = checkUserActive(user)
+	if err != nil {
+		return res, err
+	}
+
+	err = usecase.checkPasswordAge(ctx, user)
+	if err != nil {
+		return res, err
+	}
+
+	usecase.rehashPassword(ctx, user, req.Password)
+
 	isNeedMfa, err := usecase.isNeedMfa(ctx, user.ID)
 	if err != nil {
 		return res, err
 	}
 
-	auth, _, err := usecase.generateAuth(ctx, user, isNeedMfa)
+	var auth model.UserAuth
+	err = usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		auth, _, err = usecase.generateAuth(ctx, user, isNeedMfa)
+		if err != nil {
+			return err
+		}
+
+		return usecase.recordAuditLog(ctx, model.AuditLog{
+			ActorID:    user.ID,
+			Action:     model.AuditLogActionLogin,
+			TargetType: model.AuditLogTargetUser,
+			TargetID:   user.ID,
+		}, nil, nil)
+	})
 	if err != nil {
 		return res, err
 	}
@@ -268,6 +333,13 @@ func (usecase *Usecase) RefreshSession(ctx context.Context, req request.RefreshS
 	if auth.ID == 0 {
 		return res, lib.ErrorUnauthorized
 	}
+	if auth.RotatedAt != nil {
+		err = usecase.handleRefreshTokenReuse(ctx, auth)
+		if err != nil {
+			return res, err
+		}
+		return res, lib.ErrorUnauthorized
+	}
 	if time.Now().After(auth.RefreshTokenExpiredAt) {
 		return res, lib.ErrorUnauthorized
 	}
@@ -284,12 +356,19 @@ func (usecase *Usecase) RefreshSession(ctx context.Context, req request.RefreshS
 		return res, notFoundError
 	}
 
-	auth, err = usecase.generateRefreshAuth(ctx, user, auth)
+	newAuth, err := usecase.generateRefreshAuth(ctx, user, auth)
+	if errors.Is(err, errRefreshTokenReused) {
+		err = usecase.handleRefreshTokenReuse(ctx, auth)
+		if err != nil {
+			return res, err
+		}
+		return res, lib.ErrorUnauthorized
+	}
 	if err != nil {
 		return res, err
 	}
 
-	return response.NewAuth(auth, user, false), nil
+	return response.NewAuth(newAuth, user, false), nil
 }
 
 func (usecase *Usecase) SendOtp(ctx context.Context, req request.SendOtp) error {
@@ -311,8 +390,15 @@ func (usecase *Usecase) SendOtp(ctx context.Context, req request.SendOtp) error
 		notFoundError.Message = "User Not Found"
 		return notFoundError
 	}
+	if req.Channel != constant.OtpChannelEmail && user.PhoneNumber == "" {
+		validationError := lib.ErrorValidation
+		validationError.ErrDetails = map[string]any{
+			"channel": "user does not have a phone number",
+		}
+		return validationError
+	}
 
-	err = usecase.checkSendOtpRateLimit(ctx, user.ID, constant.OtpTypeLogin)
+	err = usecase.checkSendOtpRateLimit(ctx, user.ID, constant.OtpTypeLogin, req.Channel)
 	if err != nil {
 		return err
 	}
@@ -337,24 +423,41 @@ func (usecase *Usecase) SendOtp(ctx context.Context, req request.SendOtp) error
 			return err
 		}
 
-		err = usecase.repo.PublishTask(ctx, constant.TaskTypeEmailSend, request.SendEmailPayload{
-			To:           []string{user.Email},
-			TemplateName: "otp.html",
-			TemplateData: map[string]any{
-				"otp_code": otpCode,
-			},
-			Subject: "OTP",
-		})
+		err = usecase.publishOtp(ctx, user, req.Channel, otpCode)
 		if err != nil {
 			return err
 		}
 
-		return usecase.updateSendOtpRateLimit(ctx, user.ID, constant.OtpTypeLogin)
+		return usecase.updateSendOtpRateLimit(ctx, user.ID, constant.OtpTypeLogin, req.Channel)
 	})
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
 
+// publishOtp publish the otp code delivery task for the selected channel
+func (usecase *Usecase) publishOtp(ctx context.Context, user model.User, otpChannel, otpCode string) error {
+	switch otpChannel {
+	case constant.OtpChannelSms, constant.OtpChannelWhatsapp:
+		return usecase.repo.PublishTask(ctx, constant.TaskTypeSmsSend, request.SendSmsPayload{
+			To:      user.PhoneNumber,
+			Channel: otpChannel,
+			Message: fmt.Sprintf("Your OTP code is %s. Do not share this code with anyone.", otpCode),
+		})
+	default:
+		return usecase.repo.PublishTask(ctx, constant.TaskTypeEmailSend, request.SendEmailPayload{
+			To:           []string{user.Email},
+			TemplateName: "otp.html",
+			TemplateData: map[string]any{
+				"otp_code": otpCode,
+			},
+			Subject: "OTP",
+		})
+	}
+}
+
 func (usecase *Usecase) ValidateOtp(ctx context.Context, req request.ValidateMfaOtp) (response.Auth, error) {
 	ctx, span := signoz.StartSpan(ctx, "usecase.ValidateOtp")
 	defer span.Finish()
@@ -370,19 +473,46 @@ func (usecase *Usecase) ValidateOtp(ctx context.Context, req request.ValidateMfa
 		notFoundError.Message = "User Not Found"
 		return response.Auth{}, notFoundError
 	}
-	if user.OtpSecret == "" {
-		return response.Auth{}, lib.ErrorOtpInvalid
+
+	err = usecase.checkMfaLockout(ctx, user.ID)
+	if err != nil {
+		return response.Auth{}, err
 	}
 
-	validateOtp, err := auth.ValidateOtpCode(ctx, req.OtpCode, user.OtpSecret, usecase.config.TOTP_PERIOD)
+	var validateOtp bool
+	if req.RecoveryCode != "" {
+		validateOtp, err = usecase.useRecoveryCode(ctx, user.ID, req.RecoveryCode)
+	} else {
+		validateOtp, err = usecase.validateMfaOtpCode(ctx, user, req.OtpCode)
+	}
 	if err != nil {
 		return response.Auth{}, err
 	}
 	if !validateOtp {
+		err = usecase.recordMfaFailure(ctx, user.ID)
+		if err != nil {
+			return response.Auth{}, err
+		}
+
+		err = usecase.recordAuditLog(ctx, model.AuditLog{
+			ActorID:    user.ID,
+			Action:     model.AuditLogActionMfaValidateFailed,
+			TargetType: model.AuditLogTargetUser,
+			TargetID:   user.ID,
+		}, nil, nil)
+		if err != nil {
+			return response.Auth{}, err
+		}
 		return response.Auth{}, lib.ErrorOtpInvalid
 	}
 
+	err = usecase.resetMfaFailure(ctx, user.ID)
+	if err != nil {
+		return response.Auth{}, err
+	}
+
 	var auth model.UserAuth
+	var recoveryCodes []string
 	err = usecase.repo.Transaction(ctx, func(ctx context.Context) error {
 		// Generate non-mfa auth
 		auth, _, err = usecase.generateAuth(ctx, user, false)
@@ -390,10 +520,33 @@ func (usecase *Usecase) ValidateOtp(ctx context.Context, req request.ValidateMfa
 			return err
 		}
 
-		return usecase.repo.SetMfaFlag(ctx, user.ID)
+		err = usecase.repo.SetMfaFlag(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+
+		// The first validated otp enrol the email / sms otp as the factor of the user
+		if req.RecoveryCode == "" {
+			recoveryCodes, err = usecase.issueRecoveryCodes(ctx, user.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return usecase.recordAuditLog(ctx, model.AuditLog{
+			ActorID:    user.ID,
+			Action:     model.AuditLogActionMfaValidate,
+			TargetType: model.AuditLogTargetUser,
+			TargetID:   user.ID,
+		}, nil, nil)
 	})
+	if err != nil {
+		return response.Auth{}, err
+	}
 
-	return response.NewAuth(auth, user, false), nil
+	res := response.NewAuth(auth, user, false)
+	res.RecoveryCodes = recoveryCodes
+	return res, nil
 }
 
 func (usecase *Usecase) ForgotPassword(ctx context.Context, req request.ForgotPassword) (err error) {
@@ -424,23 +577,14 @@ func (usecase *Usecase) ForgotPassword(ctx context.Context, req request.ForgotPa
 		return verificationDelayError
 	}
 
-	isUsed := false
-	userVerification, err := usecase.repo.GetUserVerification(ctx, request.GetUserVerification{
-		Type:   model.UserVerificationTypeResetPassword,
-		UserID: user.ID,
-		IsUsed: &isUsed,
-	})
+	userVerification, err := usecase.getActiveUserVerification(ctx, user.ID, model.UserVerificationTypeResetPassword)
 	if err != nil {
 		return err
 	}
 	if userVerification.ID == 0 {
-		timeNow := time.Now()
-		userVerification, err = usecase.repo.CreateUserVerification(ctx, model.UserVerification{
-			Type:      model.UserVerificationTypeResetPassword,
-			UserID:    user.ID,
-			Code:      lib.GenerateUUID(),
-			CreatedAt: timeNow,
-			UpdatedAt: timeNow,
+		userVerification, err = usecase.createUserVerification(ctx, model.UserVerification{
+			Type:   model.UserVerificationTypeResetPassword,
+			UserID: user.ID,
 		})
 		if err != nil {
 			return err
@@ -469,6 +613,7 @@ func (usecase *Usecase) ResetPassword(ctx context.Context, req request.ResetPass
 	defer span.Finish()
 
 	userVerification, err := usecase.repo.GetUserVerification(ctx, request.GetUserVerification{
+		Type: model.UserVerificationTypeResetPassword,
 		Code: req.Code,
 	})
 	if err != nil {
@@ -480,12 +625,9 @@ func (usecase *Usecase) ResetPassword(ctx context.Context, req request.ResetPass
 		return notFoundError
 	}
 
-	if userVerification.ExpiredAt != nil && !userVerification.ExpiredAt.IsZero() {
-		return lib.ErrorVerificationInactive
-	}
-
-	if userVerification.UsedAt != nil && !userVerification.UsedAt.IsZero() {
-		return lib.ErrorVerificationInactive
+	err = checkUserVerification(userVerification)
+	if err != nil {
+		return err
 	}
 
 	user, err := usecase.repo.GetUser(ctx, request.GetUser{
@@ -500,34 +642,66 @@ func (usecase *Usecase) ResetPassword(ctx context.Context, req request.ResetPass
 		return notFoundError
 	}
 
+	err = usecase.validatePassword(ctx, "new_password", req.NewPassword, user)
+	if err != nil {
+		return err
+	}
+
+	newEncryptedPassword, err := usecase.generatePasswordHash(ctx, req.NewPassword)
+	if err != nil {
+		return err
+	}
+
 	return usecase.repo.Transaction(ctx, func(ctx context.Context) error {
 		timeNow := time.Now()
 
-		newEncryptedPassword, err := lib.GeneratePasswordHash(req.NewPassword)
+		before := user
+		user.EncryptedPassword = newEncryptedPassword
+		user.PasswordChangedAt = &timeNow
+		user.UpdatedAt = timeNow
+		_, err := usecase.repo.UpdateUser(ctx, user)
 		if err != nil {
 			return err
 		}
 
-		user.EncryptedPassword = newEncryptedPassword
-		user.UpdatedAt = timeNow
-		_, err = usecase.repo.UpdateUser(ctx, user)
+		err = usecase.recordAuditLog(ctx, model.AuditLog{
+			ActorID:    user.ID,
+			Action:     model.AuditLogActionPasswordReset,
+			TargetType: model.AuditLogTargetUser,
+			TargetID:   user.ID,
+		}, before, user)
 		if err != nil {
 			return err
 		}
 
-		userVerification.ExpiredAt = &timeNow
-		userVerification.UsedAt = &timeNow
-		userVerification.UpdatedAt = timeNow
-		_, err = usecase.repo.UpdateUserVerification(ctx, userVerification)
+		err = usecase.recordPasswordHistory(ctx, user)
 		if err != nil {
 			return err
 		}
 
-		return nil
+		err = usecase.useUserVerification(ctx, userVerification, timeNow)
+		if err != nil {
+			return err
+		}
+
+		// Every existing session is revoked since the old password may have been compromised
+		auths, err := usecase.repo.GetAuths(ctx, request.GetAuths{
+			UserID: user.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		return usecase.revokeAuths(ctx, auths)
 	})
 }
 
 func (usecase *Usecase) BasicAuth(ctx context.Context, req request.BasicAuth) (isAuthenticated bool, err error) {
+	err = usecase.checkLoginLockout(ctx, req.Email)
+	if err != nil {
+		return false, err
+	}
+
 	user, err := usecase.repo.GetUser(ctx, request.GetUser{
 		Email: req.Email,
 	})
@@ -535,6 +709,11 @@ func (usecase *Usecase) BasicAuth(ctx context.Context, req request.BasicAuth) (i
 		return false, err
 	}
 	if user.ID == 0 {
+		err = usecase.recordLoginFailure(ctx, user, req.Email)
+		if err != nil {
+			return false, err
+		}
+
 		notFoundError := lib.ErrorNotFound
 		notFoundError.Message = "User Not Found"
 		return false, notFoundError
@@ -542,60 +721,32 @@ func (usecase *Usecase) BasicAuth(ctx context.Context, req request.BasicAuth) (i
 
 	err = lib.CompareHashAndPassword(user.EncryptedPassword, req.Password)
 	if err != nil {
+		err = usecase.recordLoginFailure(ctx, user, req.Email)
+		if err != nil {
+			return false, err
+		}
 		return false, lib.ErrorWrongCredential
 	}
 
-	isAuthenticated = true
-	return isAuthenticated, nil
-}
-
-func (usecase *Usecase) SsoGoogle(ctx context.Context, req request.SsoGoogle) (res response.Auth, err error) {
-	ctx, span := signoz.StartSpan(ctx, "usecase.SsoGoogle")
-	defer span.Finish()
-
-	idTokenClaim, err := usecase.verifyGoogleIdToken(ctx, req.IdToken)
+	err = usecase.resetLoginFailure(ctx, req.Email)
 	if err != nil {
-		return res, err
+		return false, err
 	}
 
-	user, err := usecase.repo.GetUser(ctx, request.GetUser{
-		Email: idTokenClaim.Claims["email"].(string),
-	})
+	err = checkUserActive(user)
 	if err != nil {
-		return res, err
-	}
-
-	if user.ID == 0 {
-		// Register
-		err = usecase.repo.Transaction(ctx, func(ctx context.Context) error {
-			timeNow := time.Now()
-			user, err = usecase.repo.CreateUser(ctx, model.User{
-				Name:       idTokenClaim.Claims["name"].(string),
-				Email:      idTokenClaim.Claims["email"].(string),
-				IsActive:   true,
-				IsVerified: true,
-				CreatedAt:  timeNow,
-				UpdatedAt:  timeNow,
-			})
-			return err
-		})
-		if err != nil {
-			return res, err
-		}
+		return false, err
 	}
 
-	// Generate auth for register & login
-	isNeedMfa, err := usecase.isNeedMfa(ctx, user.ID)
+	err = usecase.checkPasswordAge(ctx, user)
 	if err != nil {
-		return res, err
+		return false, err
 	}
 
-	auth, _, err := usecase.generateAuth(ctx, user, isNeedMfa)
-	if err != nil {
-		return res, err
-	}
+	usecase.rehashPassword(ctx, user, req.Password)
 
-	return response.NewAuth(auth, user, isNeedMfa), nil
+	isAuthenticated = true
+	return isAuthenticated, nil
 }
 
 func (usecase *Usecase) GetIDToken(ctx context.Context, accessToken string) (string, error) {
@@ -606,14 +757,28 @@ func (usecase *Usecase) GetIDToken(ctx context.Context, accessToken string) (str
 	return accessTokenClaims.IDToken, err
 }
 
+// IsTrustedProxy check whether the ip belongs to one of the configured trusted proxies
+func (usecase *Usecase) IsTrustedProxy(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+
+	addr = addr.Unmap()
+	for _, prefix := range usecase.config.TRUSTED_PROXIES {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
 func (usecase *Usecase) ValidateWebsocketAPIKey(ctx context.Context, apiKey string) bool {
 	return apiKey == usecase.config.WEBSOCKET_API_KEY
 }
 
 func (usecase *Usecase) ParseIDToken(ctx context.Context, idToken string) (*auth.IDTokenClaims, error) {
-	token, err := jwt.ParseWithClaims(idToken, &auth.IDTokenClaims{}, func(token *jwt.Token) (any, error) {
-		return []byte(usecase.config.ID_TOKEN_HMAC_KEY), nil
-	})
+	token, err := jwt.ParseWithClaims(idToken, &auth.IDTokenClaims{}, usecase.keySet.Keyfunc)
 	if err != nil {
 		logger.LogError(ctx, "Error jwt.ParseWithClaims", []zap.Field{
 			zap.Error(err),
@@ -646,6 +811,11 @@ func (usecase *Usecase) isNeedMfa(ctx context.Context, userId uint) (bool, error
 }
 
 func (usecase *Usecase) generateAuth(ctx context.Context, user model.User, isNeedMfa bool) (model.UserAuth, bool, error) {
+	err := checkUserActive(user)
+	if err != nil {
+		return model.UserAuth{}, false, err
+	}
+
 	accessToken, refreshToken, idToken, accessTokenExp, refreshTokenExp, idTokenExp, err := usecase.generateAuthToken(ctx, user, isNeedMfa)
 	if err != nil {
 		return model.UserAuth{}, false, err
@@ -660,6 +830,8 @@ func (usecase *Usecase) generateAuth(ctx context.Context, user model.User, isNee
 	}
 
 	if !isNeedMfa {
+		auth = setAuthDevice(ctx, auth)
+		auth.FamilyID = lib.GenerateUUID()
 		auth.RefreshToken = refreshToken
 		auth.RefreshTokenExpiredAt = refreshTokenExp
 		auth, err = usecase.repo.CreateAuth(ctx, auth)
@@ -672,30 +844,71 @@ func (usecase *Usecase) generateAuth(ctx context.Context, user model.User, isNee
 }
 
 // generateRefreshAuth creates new non-mfa (note: refresh flow bypasses MFA) access, ID, and refresh tokens for an existing user session.
-// It updates the provided auth record with new tokens while preserving the original refresh token expiration.
-// Returns the updated auth record.
+// The provided auth record is marked as rotated and its access token is invalidated, then a new auth record is created
+// in the same token family while preserving the original refresh token expiration.
+// Returns the new auth record.
 func (usecase *Usecase) generateRefreshAuth(ctx context.Context, user model.User, auth model.UserAuth) (model.UserAuth, error) {
+	err := checkUserActive(user)
+	if err != nil {
+		return model.UserAuth{}, err
+	}
+
 	accessToken, refreshToken, idToken, accessTokenExp, _, idTokenExp, err := usecase.generateAuthToken(ctx, user, false)
 	if err != nil {
 		return model.UserAuth{}, err
 	}
 
-	auth.AccessToken = accessToken
-	auth.RefreshToken = refreshToken
-	auth.IDToken = idToken
-	auth.AccessTokenExpiredAt = accessTokenExp
-	auth.IDTokenExpiredAt = idTokenExp
-	_, err = usecase.repo.UpdateAuth(ctx, auth)
+	newAuth := model.UserAuth{
+		UserID:                user.ID,
+		FamilyID:              auth.FamilyID,
+		AccessToken:           accessToken,
+		RefreshToken:          refreshToken,
+		IDToken:               idToken,
+		AccessTokenExpiredAt:  accessTokenExp,
+		RefreshTokenExpiredAt: auth.RefreshTokenExpiredAt,
+		IDTokenExpiredAt:      idTokenExp,
+	}
+	err = usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		isRotated, err := usecase.repo.RotateAuth(ctx, auth.ID, time.Now())
+		if err != nil {
+			return err
+		}
+		if !isRotated {
+			return errRefreshTokenReused
+		}
+
+		err = usecase.repo.DelAccessTokens(ctx, auth.AccessToken)
+		if err != nil {
+			return err
+		}
+
+		newAuth, err = usecase.repo.CreateAuth(ctx, setAuthDevice(ctx, newAuth))
+		if err != nil {
+			return err
+		}
+
+		return usecase.recordAuditLog(ctx, model.AuditLog{
+			ActorID:    user.ID,
+			Action:     model.AuditLogActionSessionRefresh,
+			TargetType: model.AuditLogTargetUser,
+			TargetID:   user.ID,
+		}, nil, nil)
+	})
 	if err != nil {
 		return model.UserAuth{}, err
 	}
 
-	return auth, nil
+	return newAuth, nil
 }
 
 func (usecase *Usecase) generateAuthToken(ctx context.Context, user model.User, isMfaToken bool) (accessToken, refreshToken, idToken string, accessTokenExp, refreshTokenExp, idTokenExp time.Time, err error) {
 	timeNow := time.Now()
 
+	roles, permissions, err := usecase.getUserRolesAndPermissions(ctx, user.ID)
+	if err != nil {
+		return "", "", "", time.Time{}, time.Time{}, time.Time{}, err
+	}
+
 	idTokenExp = timeNow.Add(time.Duration(usecase.config.ID_TOKEN_TTL) * time.Second)
 	idToken, err = usecase.generateIDToken(ctx, auth.IDTokenClaims{
 		RegisteredClaims: jwt.RegisteredClaims{
@@ -706,8 +919,10 @@ func (usecase *Usecase) generateAuthToken(ctx context.Context, user model.User,
 			Subject:   strconv.Itoa(int(user.ID)),
 			Audience:  []string{constant.DefaultAudience},
 		},
-		UserID:     user.ID,
-		IsMfaToken: isMfaToken,
+		UserID:      user.ID,
+		IsMfaToken:  isMfaToken,
+		Roles:       roles,
+		Permissions: permissions,
 	})
 	if err != nil {
 		return "", "", "", time.Time{}, time.Time{}, time.Time{}, err
@@ -776,10 +991,9 @@ func (usecase *Usecase) generateAccessToken(ctx context.Context, claims auth.Acc
 }
 
 func (usecase *Usecase) generateIDToken(ctx context.Context, claims auth.IDTokenClaims) (string, error) {
-	idToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedIDToken, err := idToken.SignedString([]byte(usecase.config.ID_TOKEN_HMAC_KEY))
+	signedIDToken, err := usecase.keySet.Sign(claims)
 	if err != nil {
-		logger.LogError(ctx, "error idToken.SignedString", []zap.Field{
+		logger.LogError(ctx, "error keySet.Sign", []zap.Field{
 			zap.Error(err),
 			zap.Strings("tags", []string{"usecase", "generateIDToken"}),
 		}...)
@@ -788,8 +1002,8 @@ func (usecase *Usecase) generateIDToken(ctx context.Context, claims auth.IDToken
 	return signedIDToken, nil
 }
 
-func (usecase *Usecase) checkSendOtpRateLimit(ctx context.Context, identifier uint, otpType string) error {
-	ctr, sendOtpRateLimitTtl, err := usecase.repo.GetSendOtpRateLimitCtrWithTtl(ctx, identifier, otpType)
+func (usecase *Usecase) checkSendOtpRateLimit(ctx context.Context, identifier uint, otpType, otpChannel string) error {
+	ctr, sendOtpRateLimitTtl, err := usecase.repo.GetSendOtpRateLimitCtrWithTtl(ctx, identifier, otpType, otpChannel)
 	if err != nil {
 		return err
 	}
@@ -801,7 +1015,7 @@ func (usecase *Usecase) checkSendOtpRateLimit(ctx context.Context, identifier ui
 		return otpRateLimitError
 	}
 
-	sendOtpDelayTtl, err := usecase.repo.TtlSendOtpDelay(ctx, identifier, otpType)
+	sendOtpDelayTtl, err := usecase.repo.TtlSendOtpDelay(ctx, identifier, otpType, otpChannel)
 	if err != nil {
 		return err
 	}
@@ -816,19 +1030,19 @@ func (usecase *Usecase) checkSendOtpRateLimit(ctx context.Context, identifier ui
 	return nil
 }
 
-func (usecase *Usecase) updateSendOtpRateLimit(ctx context.Context, identifier uint, otpType string) error {
-	err := usecase.repo.SetSendOtpDelay(ctx, identifier, otpType)
+func (usecase *Usecase) updateSendOtpRateLimit(ctx context.Context, identifier uint, otpType, otpChannel string) error {
+	err := usecase.repo.SetSendOtpDelay(ctx, identifier, otpType, otpChannel)
 	if err != nil {
 		return err
 	}
 
-	newCtr, err := usecase.repo.IncrSendOtpRateLimitCtr(ctx, identifier, otpType)
+	newCtr, err := usecase.repo.IncrSendOtpRateLimitCtr(ctx, identifier, otpType, otpChannel)
 	if err != nil {
 		return err
 	}
 
 	if newCtr == 1 {
-		err = usecase.repo.ExpSendOtpRateLimitCtr(ctx, identifier, otpType)
+		err = usecase.repo.ExpSendOtpRateLimitCtr(ctx, identifier, otpType, otpChannel)
 		if err != nil {
 			return err
 		}
@@ -837,6 +1051,19 @@ func (usecase *Usecase) updateSendOtpRateLimit(ctx context.Context, identifier u
 	return nil
 }
 
-func (usecase *Usecase) verifyGoogleIdToken(ctx context.Context, idToken string) (*idtoken.Payload, error) {
-	return idtoken.Validate(ctx, idToken, "")
+// GetJwks return the public keys used to verify the id tokens, so other services can verify them without shared secret
+func (usecase *Usecase) GetJwks(ctx context.Context) (oidc.JSONWebKeySet, error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.GetJwks")
+	defer span.Finish()
+
+	jwks, err := usecase.keySet.JWKS()
+	if err != nil {
+		logger.LogError(ctx, "error keySet.JWKS", []zap.Field{
+			zap.Error(err),
+			zap.Strings("tags", []string{"usecase", "GetJwks"}),
+		}...)
+		return jwks, err
+	}
+
+	return jwks, nil
 }
@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"app/lib"
+	"app/lib/logger"
+	"app/lib/signoz"
+	"app/lib/websocket"
+	"app/model"
+	"app/request"
+	"app/response"
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// GetJob get the status of a job owned by the user, system jobs have no owner so a caller without user
+// such as an api client can never read them
+func (usecase *Usecase) GetJob(ctx context.Context, req request.GetJob) (res response.Job, err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.GetJob")
+	defer span.Finish()
+
+	if req.UserID == 0 {
+		return res, lib.ErrorUnauthorized
+	}
+
+	job, err := usecase.repo.GetJob(ctx, req.ID)
+	if err != nil {
+		return res, err
+	}
+	if job.ID == "" || job.UserID != req.UserID {
+		notFoundError := lib.ErrorNotFound
+		notFoundError.Message = "Job Not Found"
+		return res, notFoundError
+	}
+
+	return response.NewJob(job), nil
+}
+
+// StartJob mark the job as running, it is only called for the tasks of constant.JobTaskTypes
+func (usecase *Usecase) StartJob(ctx context.Context, id string) error {
+	ctx, span := signoz.StartSpan(ctx, "usecase.StartJob")
+	defer span.Finish()
+
+	job, err := usecase.repo.GetJob(ctx, id)
+	if err != nil || job.ID == "" {
+		return err
+	}
+
+	timeNow := time.Now()
+	job.State = model.JobStateRunning
+	job.StartedAt = &timeNow
+	return usecase.updateJob(ctx, job)
+}
+
+// FinishJob mark the job as completed or failed, a failed task which is going to be retried is marked as retrying
+func (usecase *Usecase) FinishJob(ctx context.Context, id string, processErr error, willRetry bool) error {
+	ctx, span := signoz.StartSpan(ctx, "usecase.FinishJob")
+	defer span.Finish()
+
+	job, err := usecase.repo.GetJob(ctx, id)
+	if err != nil || job.ID == "" {
+		return err
+	}
+
+	timeNow := time.Now()
+	switch {
+	case processErr == nil:
+		job.State = model.JobStateCompleted
+		job.Progress = 100
+		job.Error = ""
+		job.FinishedAt = &timeNow
+	case willRetry:
+		job.State = model.JobStateRetrying
+		job.Error = processErr.Error()
+	default:
+		job.State = model.JobStateFailed
+		job.Error = processErr.Error()
+		job.FinishedAt = &timeNow
+	}
+	return usecase.updateJob(ctx, job)
+}
+
+// SetJobProgress update the progress in percent of the job processed in ctx, it does nothing outside of a job
+func (usecase *Usecase) SetJobProgress(ctx context.Context, progress int) error {
+	ctx, span := signoz.StartSpan(ctx, "usecase.SetJobProgress")
+	defer span.Finish()
+
+	progress = min(max(progress, 0), 100)
+	job, err := usecase.getCurrentJob(ctx)
+	if err != nil || job.ID == "" || job.Progress == progress {
+		return err
+	}
+
+	job.Progress = progress
+	return usecase.updateJob(ctx, job)
+}
+
+// SetJobResult store the result payload of the job processed in ctx, it does nothing outside of a job
+func (usecase *Usecase) SetJobResult(ctx context.Context, result any) error {
+	ctx, span := signoz.StartSpan(ctx, "usecase.SetJobResult")
+	defer span.Finish()
+
+	job, err := usecase.getCurrentJob(ctx)
+	if err != nil || job.ID == "" {
+		return err
+	}
+
+	resultBytes, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	job.Result = string(resultBytes)
+	return usecase.updateJob(ctx, job)
+}
+
+// CleanupJobs permanently remove the completed or failed jobs once they pass the retention period
+func (usecase *Usecase) CleanupJobs(ctx context.Context) error {
+	ctx, span := signoz.StartSpan(ctx, "usecase.CleanupJobs")
+	defer span.Finish()
+
+	threshold := time.Now().Add(-time.Duration(usecase.config.JOB_RETENTION) * time.Second)
+	deleted, err := usecase.repo.DeleteStaleJobs(ctx, threshold)
+	if err != nil {
+		return err
+	}
+
+	logger.LogInfo(ctx, "Stale jobs cleaned up", []zap.Field{
+		zap.Int64("deleted", deleted),
+		zap.Strings("tags", []string{"usecase", "CleanupJobs"}),
+	}...)
+	return nil
+}
+
+// getCurrentJob get the job of the task processed in ctx, the worker set the task id as the process id
+func (usecase *Usecase) getCurrentJob(ctx context.Context) (model.Job, error) {
+	taskId, _ := ctx.Value(logger.CtxProcessID).(string)
+	if taskId == "" {
+		return model.Job{}, nil
+	}
+	return usecase.repo.GetJob(ctx, taskId)
+}
+
+// updateJob save the job and send the update to the owner through websocket,
+// a failed websocket message is only logged since the status can still be polled
+func (usecase *Usecase) updateJob(ctx context.Context, job model.Job) error {
+	job, err := usecase.repo.UpdateJob(ctx, job)
+	if err != nil {
+		return err
+	}
+
+	if job.UserID == 0 {
+		return nil
+	}
+
+	message := websocket.Message{
+		MessageType: websocket.MessageTypeJob,
+		Job: &websocket.Job{
+			ID:       job.ID,
+			Type:     job.Type,
+			State:    job.State,
+			Progress: job.Progress,
+			Error:    job.Error,
+		},
+		UserIDs:   []uint{job.UserID},
+		Timestamp: time.Now(),
+	}
+	if job.Result != "" {
+		message.Job.Result = json.RawMessage(job.Result)
+	}
+
+	err = usecase.repo.BroadcastWebsocketMessage(ctx, message)
+	if err != nil {
+		logger.LogError(ctx, "error broadcast job update", []zap.Field{
+			zap.Error(err),
+			zap.String("job_id", job.ID),
+			zap.Strings("tags", []string{"usecase", "updateJob"}),
+		}...)
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+package usecase
+
+import (
+	"app/lib"
+	"app/lib/auth"
+	"app/lib/constant"
+	"app/lib/logger"
+	"app/model"
+	"app/request"
+	"context"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// checkLoginLockout reject the attempt when either the account or the client ip is currently locked
+func (usecase *Usecase) checkLoginLockout(ctx context.Context, email string) error {
+	lockoutIdentifiers := usecase.getLockoutIdentifiers(ctx, email)
+	for scope, identifier := range lockoutIdentifiers {
+		loginLockTtl, err := usecase.repo.TtlLoginLock(ctx, scope, identifier)
+		if err != nil {
+			return err
+		}
+		if loginLockTtl > 0 {
+			accountLockedError := lib.ErrorAccountLocked
+			accountLockedError.ErrDetails = map[string]any{
+				"remaining_ttl": loginLockTtl / time.Second,
+			}
+			return accountLockedError
+		}
+	}
+
+	return nil
+}
+
+// recordLoginFailure increment the failed attempt counters and lock the account or ip once the limit is reached,
+// every consecutive lockout doubles the lockout duration up to LOGIN_LOCKOUT_MAX_TTL
+func (usecase *Usecase) recordLoginFailure(ctx context.Context, user model.User, email string) error {
+	maxFailedAttempts := map[string]int{
+		constant.LockoutScopeAccount: usecase.config.LOGIN_MAX_FAILED_ATTEMPT,
+		constant.LockoutScopeIP:      usecase.config.LOGIN_IP_MAX_FAILED_ATTEMPT,
+	}
+
+	lockoutIdentifiers := usecase.getLockoutIdentifiers(ctx, email)
+	for scope, identifier := range lockoutIdentifiers {
+		newCtr, err := usecase.repo.IncrLoginFailedCtr(ctx, scope, identifier)
+		if err != nil {
+			return err
+		}
+		if newCtr == 1 {
+			err = usecase.repo.ExpLoginFailedCtr(ctx, scope, identifier)
+			if err != nil {
+				return err
+			}
+		}
+		if newCtr < int64(maxFailedAttempts[scope]) {
+			continue
+		}
+
+		lockLevel, err := usecase.repo.IncrLoginLockLevel(ctx, scope, identifier)
+		if err != nil {
+			return err
+		}
+
+		lockoutTtl := usecase.getLoginLockoutTtl(lockLevel)
+		err = usecase.repo.SetLoginLock(ctx, scope, identifier, lockoutTtl)
+		if err != nil {
+			return err
+		}
+
+		err = usecase.repo.DelLoginFailedCtr(ctx, scope, identifier)
+		if err != nil {
+			return err
+		}
+
+		logger.LogWarn(ctx, "Login locked after too many failed attempts", []zap.Field{
+			zap.String("lockout_scope", scope),
+			zap.String("identifier", identifier),
+			zap.Int64("lock_level", lockLevel),
+			zap.Duration("lockout_ttl", lockoutTtl),
+			zap.Strings("tags", []string{"usecase", "recordLoginFailure", "security"}),
+		}...)
+
+		if scope == constant.LockoutScopeAccount && user.ID != 0 {
+			err = usecase.repo.PublishTask(ctx, constant.TaskTypeEmailSend, request.SendEmailPayload{
+				To:           []string{user.Email},
+				TemplateName: "account_locked.html",
+				TemplateData: map[string]any{
+					"lockout_minutes": int(math.Ceil(lockoutTtl.Minutes())),
+					"ip_address":      auth.GetDeviceFromCtx(ctx).IPAddress,
+				},
+				Subject: "Account Locked",
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// resetLoginFailure clear the account failed attempt counter and lockout level after a successful login,
+// the ip counter is kept so a single client can not reset it by owning one valid account
+func (usecase *Usecase) resetLoginFailure(ctx context.Context, email string) error {
+	identifier := strings.ToLower(email)
+	err := usecase.repo.DelLoginFailedCtr(ctx, constant.LockoutScopeAccount, identifier)
+	if err != nil {
+		return err
+	}
+
+	return usecase.repo.DelLoginLockLevel(ctx, constant.LockoutScopeAccount, identifier)
+}
+
+// checkMfaLockout reject the mfa otp validation while the user is locked after too many invalid codes
+func (usecase *Usecase) checkMfaLockout(ctx context.Context, userId uint) error {
+	mfaLockTtl, err := usecase.repo.TtlLoginLock(ctx, constant.LockoutScopeMfa, strconv.Itoa(int(userId)))
+	if err != nil {
+		return err
+	}
+	if mfaLockTtl > 0 {
+		accountLockedError := lib.ErrorAccountLocked
+		accountLockedError.ErrDetails = map[string]any{
+			"remaining_ttl": mfaLockTtl / time.Second,
+		}
+		return accountLockedError
+	}
+
+	return nil
+}
+
+// recordMfaFailure increment the invalid mfa otp counter of the user and lock the mfa validation once the limit
+// is reached, so a 6 digits code can't be brute forced within the mfa token lifetime
+func (usecase *Usecase) recordMfaFailure(ctx context.Context, userId uint) error {
+	identifier := strconv.Itoa(int(userId))
+	newCtr, err := usecase.repo.IncrLoginFailedCtr(ctx, constant.LockoutScopeMfa, identifier)
+	if err != nil {
+		return err
+	}
+	if newCtr == 1 {
+		err = usecase.repo.ExpLoginFailedCtr(ctx, constant.LockoutScopeMfa, identifier)
+		if err != nil {
+			return err
+		}
+	}
+	if newCtr < int64(usecase.config.MFA_MAX_FAILED_ATTEMPT) {
+		return nil
+	}
+
+	lockLevel, err := usecase.repo.IncrLoginLockLevel(ctx, constant.LockoutScopeMfa, identifier)
+	if err != nil {
+		return err
+	}
+
+	lockoutTtl := usecase.getLoginLockoutTtl(lockLevel)
+	err = usecase.repo.SetLoginLock(ctx, constant.LockoutScopeMfa, identifier, lockoutTtl)
+	if err != nil {
+		return err
+	}
+
+	logger.LogWarn(ctx, "Mfa locked after too many failed attempts", []zap.Field{
+		zap.Uint("user_id", userId),
+		zap.Int64("lock_level", lockLevel),
+		zap.Duration("lockout_ttl", lockoutTtl),
+		zap.Strings("tags", []string{"usecase", "recordMfaFailure", "security"}),
+	}...)
+
+	return usecase.repo.DelLoginFailedCtr(ctx, constant.LockoutScopeMfa, identifier)
+}
+
+// resetMfaFailure clear the invalid mfa otp counter and lockout level after a successful validation
+func (usecase *Usecase) resetMfaFailure(ctx context.Context, userId uint) error {
+	identifier := strconv.Itoa(int(userId))
+	err := usecase.repo.DelLoginFailedCtr(ctx, constant.LockoutScopeMfa, identifier)
+	if err != nil {
+		return err
+	}
+
+	return usecase.repo.DelLoginLockLevel(ctx, constant.LockoutScopeMfa, identifier)
+}
+
+func (usecase *Usecase) getLockoutIdentifiers(ctx context.Context, email string) map[string]string {
+	lockoutIdentifiers := map[string]string{
+		constant.LockoutScopeAccount: strings.ToLower(email),
+	}
+
+	device := auth.GetDeviceFromCtx(ctx)
+	if device.IPAddress != "" {
+		lockoutIdentifiers[constant.LockoutScopeIP] = device.IPAddress
+	}
+
+	return lockoutIdentifiers
+}
+
+func (usecase *Usecase) getLoginLockoutTtl(lockLevel int64) time.Duration {
+	lockoutTtl := float64(usecase.config.LOGIN_LOCKOUT_TTL) * math.Pow(2, float64(lockLevel-1))
+	lockoutTtl = math.Min(lockoutTtl, float64(usecase.config.LOGIN_LOCKOUT_MAX_TTL))
+	return time.Duration(lockoutTtl) * time.Second
+}
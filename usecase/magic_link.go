@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"app/lib"
+	"app/lib/constant"
+	"app/lib/signoz"
+	"app/model"
+	"app/request"
+	"app/response"
+	"context"
+	"time"
+)
+
+// SendMagicLink email a short lived login code to the user, sending is throttled by the verification delay
+func (usecase *Usecase) SendMagicLink(ctx context.Context, req request.SendMagicLink) (err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.SendMagicLink")
+	defer span.Finish()
+
+	user, err := usecase.repo.GetUser(ctx, request.GetUser{
+		Email: req.Email,
+	})
+	if err != nil {
+		return err
+	}
+	if user.ID == 0 {
+		notFoundError := lib.ErrorNotFound
+		notFoundError.Message = "User Not Found"
+		return notFoundError
+	}
+
+	verificationDelayCache, remainingTtl, err := usecase.repo.GetVerificationDelayCacheWithTtl(ctx, user.ID, model.UserVerificationTypeMagicLogin)
+	if err != nil {
+		return err
+	}
+	if verificationDelayCache != "" {
+		verificationDelayError := lib.ErrorVerificationDelay
+		verificationDelayError.ErrDetails = map[string]any{
+			"remaining_ttl": remainingTtl / time.Second,
+		}
+		return verificationDelayError
+	}
+
+	userVerification, err := usecase.getActiveUserVerification(ctx, user.ID, model.UserVerificationTypeMagicLogin)
+	if err != nil {
+		return err
+	}
+
+	return usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		if userVerification.ID == 0 {
+			userVerification, err = usecase.createUserVerification(ctx, model.UserVerification{
+				Type:   model.UserVerificationTypeMagicLogin,
+				UserID: user.ID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		err = usecase.repo.PublishTask(ctx, constant.TaskTypeEmailSend, request.SendEmailPayload{
+			To:           []string{user.Email},
+			TemplateName: "magic_login.html",
+			TemplateData: map[string]any{
+				"code": userVerification.Code,
+			},
+			Subject: "Magic Login",
+		})
+		if err != nil {
+			return err
+		}
+
+		return usecase.repo.SetVerificationDelayCache(ctx, user.ID, model.UserVerificationTypeMagicLogin)
+	})
+}
+
+// VerifyMagicLink exchange a magic login code for a session, the code can only be used once
+func (usecase *Usecase) VerifyMagicLink(ctx context.Context, req request.VerifyMagicLink) (res response.Auth, err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.VerifyMagicLink")
+	defer span.Finish()
+
+	userVerification, err := usecase.repo.GetUserVerification(ctx, request.GetUserVerification{
+		Type: model.UserVerificationTypeMagicLogin,
+		Code: req.Code,
+	})
+	if err != nil {
+		return res, err
+	}
+	if userVerification.ID == 0 {
+		notFoundError := lib.ErrorNotFound
+		notFoundError.Message = "User Verification Not Found"
+		return res, notFoundError
+	}
+
+	err = checkUserVerification(userVerification)
+	if err != nil {
+		return res, err
+	}
+
+	user, err := usecase.repo.GetUser(ctx, request.GetUser{
+		ID: userVerification.UserID,
+	})
+	if err != nil {
+		return res, err
+	}
+	if user.ID == 0 {
+		notFoundError := lib.ErrorNotFound
+		notFoundError.Message = "User Not Found"
+		return res, notFoundError
+	}
+
+	err = usecase.checkLoginLockout(ctx, user.Email)
+	if err != nil {
+		return res, err
+	}
+
+	isNeedMfa, err := usecase.isNeedMfa(ctx, user.ID)
+	if err != nil {
+		return res, err
+	}
+
+	var auth model.UserAuth
+	err = usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := usecase.useUserVerification(ctx, userVerification, time.Now())
+		if err != nil {
+			return err
+		}
+
+		auth, _, err = usecase.generateAuth(ctx, user, isNeedMfa)
+		if err != nil {
+			return err
+		}
+
+		return usecase.recordAuditLog(ctx, model.AuditLog{
+			ActorID:    user.ID,
+			Action:     model.AuditLogActionLogin,
+			TargetType: model.AuditLogTargetUser,
+			TargetID:   user.ID,
+		}, nil, nil)
+	})
+	if err != nil {
+		return res, err
+	}
+
+	return response.NewAuth(auth, user, isNeedMfa), nil
+}
@@ -0,0 +1,417 @@
+package usecase
+
+import (
+	"app/lib"
+	"app/lib/constant"
+	"app/lib/logger"
+	"app/lib/signoz"
+	"app/model"
+	"app/request"
+	"app/response"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func (usecase *Usecase) GetProfile(ctx context.Context, req request.GetProfile) (res response.Profile, err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.GetProfile")
+	defer span.Finish()
+
+	user, err := usecase.repo.GetUser(ctx, request.GetUser{
+		ID: req.UserID,
+	})
+	if err != nil {
+		return res, err
+	}
+	if user.ID == 0 {
+		notFoundError := lib.ErrorNotFound
+		notFoundError.Message = "User Not Found"
+		return res, notFoundError
+	}
+
+	roles, permissions, err := usecase.getUserRolesAndPermissions(ctx, user.ID)
+	if err != nil {
+		return res, err
+	}
+
+	avatarURL := ""
+	if user.AvatarPath != "" {
+		avatarURL, err = usecase.storage.GetFileTemporaryURL(ctx, "", user.AvatarPath)
+		if err != nil {
+			return res, err
+		}
+	}
+
+	return response.NewProfile(user, avatarURL, roles, permissions), nil
+}
+
+// UpdateProfile only update the fields a user may change by themself, email and password have their own flows
+func (usecase *Usecase) UpdateProfile(ctx context.Context, req request.UpdateProfile) (err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.UpdateProfile")
+	defer span.Finish()
+
+	user, err := usecase.repo.GetUser(ctx, request.GetUser{
+		ID: req.UserID,
+	})
+	if err != nil {
+		return err
+	}
+	if user.ID == 0 {
+		notFoundError := lib.ErrorNotFound
+		notFoundError.Message = "User Not Found"
+		return notFoundError
+	}
+
+	user.Name = req.Name
+	user.PhoneNumber = req.PhoneNumber
+	user.UpdatedAt = time.Now()
+	_, err = usecase.repo.UpdateUser(ctx, user)
+	return err
+}
+
+// UploadAvatar store the avatar through the regular upload path and replace the previous avatar of the user
+func (usecase *Usecase) UploadAvatar(ctx context.Context, req request.UploadAvatar) (res response.UploadFile, err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.UploadAvatar")
+	defer span.Finish()
+
+	user, err := usecase.repo.GetUser(ctx, request.GetUser{
+		ID: req.UserID,
+	})
+	if err != nil {
+		return res, err
+	}
+	if user.ID == 0 {
+		notFoundError := lib.ErrorNotFound
+		notFoundError.Message = "User Not Found"
+		return res, notFoundError
+	}
+
+	res, err = usecase.UploadFile(ctx, request.UploadFile{
+		FileData:   req.FileData,
+		TargetPath: constant.UploadPathAvatar,
+	})
+	if err != nil {
+		return res, err
+	}
+
+	oldAvatarPath := user.AvatarPath
+	user.AvatarPath = res.Filepath
+	user.UpdatedAt = time.Now()
+	_, err = usecase.repo.UpdateUser(ctx, user)
+	if err != nil {
+		return res, err
+	}
+
+	// The previous avatar is no longer referenced, failing to remove it is only logged
+	if oldAvatarPath != "" {
+		err = usecase.storage.RemoveFile(ctx, "", oldAvatarPath)
+		if err != nil {
+			logger.LogError(ctx, "Error RemoveFile", []zap.Field{
+				zap.Error(err),
+				zap.String("filepath", oldAvatarPath),
+				zap.Strings("tags", []string{"usecase", "UploadAvatar"}),
+			}...)
+		}
+	}
+
+	return res, nil
+}
+
+// DeleteAccount delete the account of the authenticated user, revoke all of its sessions and schedule the erasure
+// of its personal data. The password is required unless the account was created through sso and never had one
+func (usecase *Usecase) DeleteAccount(ctx context.Context, req request.DeleteAccount) (err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.DeleteAccount")
+	defer span.Finish()
+
+	user, err := usecase.repo.GetUser(ctx, request.GetUser{
+		ID: req.UserID,
+	})
+	if err != nil {
+		return err
+	}
+	if user.ID == 0 {
+		notFoundError := lib.ErrorNotFound
+		notFoundError.Message = "User Not Found"
+		return notFoundError
+	}
+
+	if user.EncryptedPassword != "" {
+		err = lib.CompareHashAndPassword(user.EncryptedPassword, req.Password)
+		if err != nil {
+			return lib.ErrorWrongCredential
+		}
+	}
+
+	return usecase.deleteUser(ctx, user)
+}
+
+// ChangePassword change the password of the authenticated user, every other session is revoked
+// while the session making the request stays active
+func (usecase *Usecase) ChangePassword(ctx context.Context, req request.ChangePassword) (err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.ChangePassword")
+	defer span.Finish()
+
+	user, err := usecase.repo.GetUser(ctx, request.GetUser{
+		ID: req.UserID,
+	})
+	if err != nil {
+		return err
+	}
+	if user.ID == 0 {
+		notFoundError := lib.ErrorNotFound
+		notFoundError.Message = "User Not Found"
+		return notFoundError
+	}
+
+	err = lib.CompareHashAndPassword(user.EncryptedPassword, req.CurrentPassword)
+	if err != nil {
+		return lib.ErrorWrongCredential
+	}
+
+	err = usecase.validatePassword(ctx, "new_password", req.NewPassword, user)
+	if err != nil {
+		return err
+	}
+
+	newEncryptedPassword, err := usecase.generatePasswordHash(ctx, req.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	currentAuth, err := usecase.repo.GetAuth(ctx, request.GetAuth{
+		UserID:      user.ID,
+		AccessToken: req.AccessToken,
+	})
+	if err != nil {
+		return err
+	}
+
+	auths, err := usecase.repo.GetAuths(ctx, request.GetAuths{
+		UserID: user.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	otherAuths := []model.UserAuth{}
+	for _, auth := range auths {
+		if currentAuth.ID > 0 && auth.FamilyID == currentAuth.FamilyID {
+			continue
+		}
+		otherAuths = append(otherAuths, auth)
+	}
+
+	return usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		timeNow := time.Now()
+		before := user
+		user.EncryptedPassword = newEncryptedPassword
+		user.PasswordChangedAt = &timeNow
+		user.UpdatedAt = timeNow
+		_, err := usecase.repo.UpdateUser(ctx, user)
+		if err != nil {
+			return err
+		}
+
+		err = usecase.recordAuditLog(ctx, model.AuditLog{
+			Action:     model.AuditLogActionPasswordChange,
+			TargetType: model.AuditLogTargetUser,
+			TargetID:   user.ID,
+		}, before, user)
+		if err != nil {
+			return err
+		}
+
+		err = usecase.recordPasswordHistory(ctx, user)
+		if err != nil {
+			return err
+		}
+
+		return usecase.revokeAuths(ctx, otherAuths)
+	})
+}
+
+// ChangeEmail send a verification code to the new email, the email is only changed after ConfirmChangeEmail
+func (usecase *Usecase) ChangeEmail(ctx context.Context, req request.ChangeEmail) (err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.ChangeEmail")
+	defer span.Finish()
+
+	user, err := usecase.repo.GetUser(ctx, request.GetUser{
+		ID: req.UserID,
+	})
+	if err != nil {
+		return err
+	}
+	if user.ID == 0 {
+		notFoundError := lib.ErrorNotFound
+		notFoundError.Message = "User Not Found"
+		return notFoundError
+	}
+
+	if user.EncryptedPassword != "" {
+		err = lib.CompareHashAndPassword(user.EncryptedPassword, req.CurrentPassword)
+		if err != nil {
+			return lib.ErrorWrongCredential
+		}
+	}
+
+	err = usecase.checkEmailAvailable(ctx, req.NewEmail)
+	if err != nil {
+		return err
+	}
+
+	verificationDelayCache, remainingTtl, err := usecase.repo.GetVerificationDelayCacheWithTtl(ctx, user.ID, model.UserVerificationTypeChangeEmail)
+	if err != nil {
+		return err
+	}
+	if verificationDelayCache != "" {
+		verificationDelayError := lib.ErrorVerificationDelay
+		verificationDelayError.ErrDetails = map[string]any{
+			"remaining_ttl": remainingTtl / time.Second,
+		}
+		return verificationDelayError
+	}
+
+	userVerification, err := usecase.getActiveUserVerification(ctx, user.ID, model.UserVerificationTypeChangeEmail)
+	if err != nil {
+		return err
+	}
+
+	return usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		// A pending change to another email is expired, only the latest requested email can be confirmed
+		if userVerification.ID > 0 && userVerification.Target != req.NewEmail {
+			isExpired, err := usecase.repo.ExpireUserVerification(ctx, userVerification.ID, time.Now())
+			if err != nil {
+				return err
+			}
+			// The pending change was confirmed by a concurrent request
+			if !isExpired {
+				return lib.ErrorVerificationInactive
+			}
+			userVerification = model.UserVerification{}
+		}
+
+		if userVerification.ID == 0 {
+			userVerification, err = usecase.createUserVerification(ctx, model.UserVerification{
+				Type:   model.UserVerificationTypeChangeEmail,
+				UserID: user.ID,
+				Target: req.NewEmail,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		err = usecase.repo.PublishTask(ctx, constant.TaskTypeEmailSend, request.SendEmailPayload{
+			To:           []string{req.NewEmail},
+			TemplateName: "change_email.html",
+			TemplateData: map[string]any{
+				"code": userVerification.Code,
+			},
+			Subject: "Change Email Verification",
+		})
+		if err != nil {
+			return err
+		}
+
+		return usecase.repo.SetVerificationDelayCache(ctx, user.ID, model.UserVerificationTypeChangeEmail)
+	})
+}
+
+// ConfirmChangeEmail swap the user email with the verified one and notify the previous email
+func (usecase *Usecase) ConfirmChangeEmail(ctx context.Context, req request.ConfirmChangeEmail) (err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.ConfirmChangeEmail")
+	defer span.Finish()
+
+	userVerification, err := usecase.repo.GetUserVerification(ctx, request.GetUserVerification{
+		Type: model.UserVerificationTypeChangeEmail,
+		Code: req.Code,
+	})
+	if err != nil {
+		return err
+	}
+	if userVerification.ID == 0 {
+		notFoundError := lib.ErrorNotFound
+		notFoundError.Message = "User Verification Not Found"
+		return notFoundError
+	}
+
+	err = checkUserVerification(userVerification)
+	if err != nil {
+		return err
+	}
+
+	user, err := usecase.repo.GetUser(ctx, request.GetUser{
+		ID: userVerification.UserID,
+	})
+	if err != nil {
+		return err
+	}
+	if user.ID == 0 {
+		notFoundError := lib.ErrorNotFound
+		notFoundError.Message = "User Not Found"
+		return notFoundError
+	}
+
+	// The email may have been registered by someone else since the change was requested
+	err = usecase.checkEmailAvailable(ctx, userVerification.Target)
+	if err != nil {
+		return err
+	}
+
+	return usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		timeNow := time.Now()
+		oldEmail := user.Email
+		before := user
+
+		user.Email = userVerification.Target
+		user.IsVerified = true
+		user.UpdatedAt = timeNow
+		_, err := usecase.repo.UpdateUser(ctx, user)
+		if err != nil {
+			return err
+		}
+
+		err = usecase.recordAuditLog(ctx, model.AuditLog{
+			ActorID:    user.ID,
+			Action:     model.AuditLogActionEmailChange,
+			TargetType: model.AuditLogTargetUser,
+			TargetID:   user.ID,
+		}, before, user)
+		if err != nil {
+			return err
+		}
+
+		err = usecase.useUserVerification(ctx, userVerification, timeNow)
+		if err != nil {
+			return err
+		}
+
+		return usecase.repo.PublishTask(ctx, constant.TaskTypeEmailSend, request.SendEmailPayload{
+			To:           []string{oldEmail},
+			TemplateName: "email_changed.html",
+			TemplateData: map[string]any{
+				"new_email": user.Email,
+			},
+			Subject: "Email Changed",
+		})
+	})
+}
+
+func (usecase *Usecase) checkEmailAvailable(ctx context.Context, email string) error {
+	checkUserEmail, err := usecase.repo.GetUser(ctx, request.GetUser{
+		Email: email,
+	})
+	if err != nil {
+		return err
+	}
+	if checkUserEmail.ID > 0 {
+		validationError := lib.ErrorValidation
+		validationError.ErrDetails = map[string]any{
+			"email": "Email already registered",
+		}
+		return validationError
+	}
+
+	return nil
+}
@@ -0,0 +1,343 @@
+package usecase
+
+import (
+	"app/lib"
+	"app/lib/auth"
+	"app/lib/constant"
+	"app/lib/logger"
+	"app/lib/signoz"
+	"app/model"
+	"app/request"
+	"app/response"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// EnrollTotp start authenticator app enrolment, the factor is only usable after it is confirmed with ConfirmTotp
+func (usecase *Usecase) EnrollTotp(ctx context.Context, req request.EnrollTotp) (res response.EnrollTotp, err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.EnrollTotp")
+	defer span.Finish()
+
+	user, err := usecase.repo.GetUser(ctx, request.GetUser{
+		ID: req.UserID,
+	})
+	if err != nil {
+		return res, err
+	}
+	if user.ID == 0 {
+		notFoundError := lib.ErrorNotFound
+		notFoundError.Message = "User Not Found"
+		return res, notFoundError
+	}
+
+	userMfaFactor, err := usecase.repo.GetUserMfaFactor(ctx, request.GetUserMfaFactor{
+		UserID: user.ID,
+		Type:   model.UserMfaFactorTypeTotp,
+	})
+	if err != nil {
+		return res, err
+	}
+	if userMfaFactor.ConfirmedAt != nil {
+		validationError := lib.ErrorValidation
+		validationError.ErrDetails = map[string]any{
+			"totp": "Authenticator app already enrolled",
+		}
+		return res, validationError
+	}
+
+	key, err := auth.GenerateTotpKey(ctx, user.Email)
+	if err != nil {
+		return res, err
+	}
+
+	qrCodePng, err := auth.GenerateTotpQrCode(ctx, key, constant.TotpQrCodePngSize)
+	if err != nil {
+		return res, err
+	}
+
+	err = usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		timeNow := time.Now()
+		var err error
+		if userMfaFactor.ID == 0 {
+			_, err = usecase.repo.CreateUserMfaFactor(ctx, model.UserMfaFactor{
+				UserID:    user.ID,
+				Type:      model.UserMfaFactorTypeTotp,
+				Secret:    key.Secret(),
+				CreatedAt: timeNow,
+				UpdatedAt: timeNow,
+			})
+		} else {
+			// Re-enrolment of unconfirmed factor replace the previous secret
+			userMfaFactor.Secret = key.Secret()
+			userMfaFactor.UpdatedAt = timeNow
+			_, err = usecase.repo.UpdateUserMfaFactor(ctx, userMfaFactor)
+		}
+		if err != nil {
+			return err
+		}
+
+		return usecase.recordAuditLog(ctx, model.AuditLog{
+			Action:     model.AuditLogActionTotpEnroll,
+			TargetType: model.AuditLogTargetUser,
+			TargetID:   user.ID,
+		}, nil, nil)
+	})
+	if err != nil {
+		return res, err
+	}
+
+	res = response.EnrollTotp{
+		Secret:    key.Secret(),
+		URI:       key.URL(),
+		QrCodePng: qrCodePng,
+	}
+	return res, nil
+}
+
+// ConfirmTotp activate the enrolled authenticator app once the user proves it generates valid code,
+// a new set of recovery codes is generated as part of the enrolment
+func (usecase *Usecase) ConfirmTotp(ctx context.Context, req request.ConfirmTotp) (res response.RecoveryCodes, err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.ConfirmTotp")
+	defer span.Finish()
+
+	isConfirmed := false
+	userMfaFactor, err := usecase.repo.GetUserMfaFactor(ctx, request.GetUserMfaFactor{
+		UserID:      req.UserID,
+		Type:        model.UserMfaFactorTypeTotp,
+		IsConfirmed: &isConfirmed,
+	})
+	if err != nil {
+		return res, err
+	}
+	if userMfaFactor.ID == 0 {
+		notFoundError := lib.ErrorNotFound
+		notFoundError.Message = "Authenticator App Enrolment Not Found"
+		return res, notFoundError
+	}
+
+	validateOtp, err := auth.ValidateOtpCode(ctx, req.OtpCode, userMfaFactor.Secret, constant.TotpAppPeriod)
+	if err != nil {
+		return res, err
+	}
+	if !validateOtp {
+		return res, lib.ErrorOtpInvalid
+	}
+
+	err = usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		timeNow := time.Now()
+		userMfaFactor.ConfirmedAt = &timeNow
+		userMfaFactor.UpdatedAt = timeNow
+		_, err := usecase.repo.UpdateUserMfaFactor(ctx, userMfaFactor)
+		if err != nil {
+			return err
+		}
+
+		res, err = usecase.generateRecoveryCodes(ctx, req.UserID)
+		if err != nil {
+			return err
+		}
+
+		return usecase.recordAuditLog(ctx, model.AuditLog{
+			Action:     model.AuditLogActionTotpConfirm,
+			TargetType: model.AuditLogTargetUser,
+			TargetID:   req.UserID,
+		}, nil, nil)
+	})
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// RegenerateRecoveryCodes replace all of the user recovery codes, the previous codes are no longer usable.
+// Recovery codes are only a fallback of a second factor, so the user must have a factor enrolled
+func (usecase *Usecase) RegenerateRecoveryCodes(ctx context.Context, req request.RegenerateRecoveryCodes) (res response.RecoveryCodes, err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.RegenerateRecoveryCodes")
+	defer span.Finish()
+
+	user, err := usecase.repo.GetUser(ctx, request.GetUser{
+		ID: req.UserID,
+	})
+	if err != nil {
+		return res, err
+	}
+	if user.ID == 0 {
+		notFoundError := lib.ErrorNotFound
+		notFoundError.Message = "User Not Found"
+		return res, notFoundError
+	}
+
+	hasMfaFactor, err := usecase.hasMfaFactor(ctx, user)
+	if err != nil {
+		return res, err
+	}
+	if !hasMfaFactor {
+		return res, lib.ErrorMfaNotEnrolled
+	}
+
+	err = usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		res, err = usecase.generateRecoveryCodes(ctx, req.UserID)
+		if err != nil {
+			return err
+		}
+
+		return usecase.recordAuditLog(ctx, model.AuditLog{
+			Action:     model.AuditLogActionRecoveryCodeRegenerate,
+			TargetType: model.AuditLogTargetUser,
+			TargetID:   req.UserID,
+		}, nil, nil)
+	})
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// validateMfaOtpCode validate otp code against every enrolled factor of the user:
+// the confirmed authenticator app and the latest email otp
+func (usecase *Usecase) validateMfaOtpCode(ctx context.Context, user model.User, otpCode string) (bool, error) {
+	isConfirmed := true
+	userMfaFactor, err := usecase.repo.GetUserMfaFactor(ctx, request.GetUserMfaFactor{
+		UserID:      user.ID,
+		Type:        model.UserMfaFactorTypeTotp,
+		IsConfirmed: &isConfirmed,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if userMfaFactor.ID > 0 {
+		validateOtp, err := auth.ValidateOtpCode(ctx, otpCode, userMfaFactor.Secret, constant.TotpAppPeriod)
+		if err != nil {
+			return false, err
+		}
+		if validateOtp {
+			return true, nil
+		}
+	}
+
+	if user.OtpSecret != "" {
+		return auth.ValidateOtpCode(ctx, otpCode, user.OtpSecret, usecase.config.TOTP_PERIOD)
+	}
+
+	return false, nil
+}
+
+// hasMfaFactor check whether the user has any second factor enrolled: the confirmed authenticator app,
+// a passkey or the email / sms otp which is set up once an otp is sent to the user
+func (usecase *Usecase) hasMfaFactor(ctx context.Context, user model.User) (bool, error) {
+	if user.OtpSecret != "" {
+		return true, nil
+	}
+
+	isConfirmed := true
+	userMfaFactor, err := usecase.repo.GetUserMfaFactor(ctx, request.GetUserMfaFactor{
+		UserID:      user.ID,
+		IsConfirmed: &isConfirmed,
+	})
+	if err != nil {
+		return false, err
+	}
+	if userMfaFactor.ID > 0 {
+		return true, nil
+	}
+
+	userCredentials, err := usecase.repo.GetUserCredentials(ctx, request.GetUserCredentials{
+		UserID: user.ID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return len(userCredentials) > 0, nil
+}
+
+// issueRecoveryCodes generate the first set of recovery codes when a factor is enrolled,
+// nothing is returned when the user already has recovery codes so an existing set is never replaced silently
+func (usecase *Usecase) issueRecoveryCodes(ctx context.Context, userId uint) ([]string, error) {
+	userRecoveryCodes, err := usecase.repo.GetUserRecoveryCodes(ctx, request.GetUserRecoveryCodes{
+		UserID: userId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(userRecoveryCodes) > 0 {
+		return nil, nil
+	}
+
+	res, err := usecase.generateRecoveryCodes(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.RecoveryCodes, nil
+}
+
+// generateRecoveryCodes delete the existing recovery codes and store the hashes of the new ones
+func (usecase *Usecase) generateRecoveryCodes(ctx context.Context, userId uint) (res response.RecoveryCodes, err error) {
+	recoveryCodes, err := auth.GenerateRecoveryCodes(ctx, constant.RecoveryCodeCount, constant.RecoveryCodeLength)
+	if err != nil {
+		return res, err
+	}
+
+	timeNow := time.Now()
+	userRecoveryCodes := []model.UserRecoveryCode{}
+	for _, recoveryCode := range recoveryCodes {
+		codeHash, err := lib.GeneratePasswordHash(recoveryCode, usecase.config.BCRYPT_COST)
+		if err != nil {
+			logger.LogError(ctx, "Error GeneratePasswordHash", []zap.Field{
+				zap.Error(err),
+				zap.Strings("tags", []string{"usecase", "generateRecoveryCodes"}),
+			}...)
+			return res, lib.ErrorInternalServer
+		}
+
+		userRecoveryCodes = append(userRecoveryCodes, model.UserRecoveryCode{
+			UserID:    userId,
+			CodeHash:  codeHash,
+			CreatedAt: timeNow,
+			UpdatedAt: timeNow,
+		})
+	}
+
+	err = usecase.repo.DeleteUserRecoveryCodes(ctx, userId)
+	if err != nil {
+		return res, err
+	}
+
+	_, err = usecase.repo.CreateUserRecoveryCodes(ctx, userRecoveryCodes)
+	if err != nil {
+		return res, err
+	}
+
+	res.RecoveryCodes = recoveryCodes
+	return res, nil
+}
+
+// useRecoveryCode mark the matching unused recovery code as used, return false when there is no match
+func (usecase *Usecase) useRecoveryCode(ctx context.Context, userId uint, recoveryCode string) (bool, error) {
+	isUsed := false
+	userRecoveryCodes, err := usecase.repo.GetUserRecoveryCodes(ctx, request.GetUserRecoveryCodes{
+		UserID: userId,
+		IsUsed: &isUsed,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	recoveryCode = auth.NormalizeRecoveryCode(recoveryCode)
+	for _, userRecoveryCode := range userRecoveryCodes {
+		err = lib.CompareHashAndPassword(userRecoveryCode.CodeHash, recoveryCode)
+		if err != nil {
+			continue
+		}
+
+		return usecase.repo.UseUserRecoveryCode(ctx, userRecoveryCode.ID, time.Now())
+	}
+
+	return false, nil
+}
@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"app/lib"
+	"app/lib/constant"
+	"app/lib/logger"
+	"app/lib/password"
+	"app/model"
+	"app/request"
+	"context"
+	"fmt"
+	"time"
+	"unicode"
+
+	"go.uber.org/zap"
+)
+
+type passwordPolicy struct {
+	MinLength        uint
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireNumber    bool
+	RequireSpecial   bool
+	HistoryDepth     uint
+	MaxAge           uint // In days, 0 to disable
+}
+
+// getPasswordPolicy read the password policy from app_settings
+func (usecase *Usecase) getPasswordPolicy(ctx context.Context) (policy passwordPolicy, err error) {
+	policy.MinLength, err = usecase.getAppSettingUint(ctx, constant.AppSettingPasswordMinLength)
+	if err != nil {
+		return policy, err
+	}
+
+	policy.RequireUppercase, err = usecase.getAppSettingBool(ctx, constant.AppSettingPasswordRequireUppercase)
+	if err != nil {
+		return policy, err
+	}
+
+	policy.RequireLowercase, err = usecase.getAppSettingBool(ctx, constant.AppSettingPasswordRequireLowercase)
+	if err != nil {
+		return policy, err
+	}
+
+	policy.RequireNumber, err = usecase.getAppSettingBool(ctx, constant.AppSettingPasswordRequireNumber)
+	if err != nil {
+		return policy, err
+	}
+
+	policy.RequireSpecial, err = usecase.getAppSettingBool(ctx, constant.AppSettingPasswordRequireSpecial)
+	if err != nil {
+		return policy, err
+	}
+
+	policy.HistoryDepth, err = usecase.getAppSettingUint(ctx, constant.AppSettingPasswordHistoryDepth)
+	if err != nil {
+		return policy, err
+	}
+
+	policy.MaxAge, err = usecase.getAppSettingUint(ctx, constant.AppSettingPasswordMaxAge)
+	if err != nil {
+		return policy, err
+	}
+
+	return policy, nil
+}
+
+// validatePassword check a new password against the password policy, the breached password list and,
+// when the user already exists, the recent passwords of the user. The error is returned as a validation error on the given field
+func (usecase *Usecase) validatePassword(ctx context.Context, field string, newPassword string, user model.User) error {
+	policy, err := usecase.getPasswordPolicy(ctx)
+	if err != nil {
+		return err
+	}
+
+	return usecase.validatePasswordWithPolicy(ctx, policy, field, newPassword, user)
+}
+
+// validatePasswordWithPolicy is validatePassword with an already loaded policy, used when validating many passwords at once
+func (usecase *Usecase) validatePasswordWithPolicy(ctx context.Context, policy passwordPolicy, field string, newPassword string, user model.User) error {
+	message := checkPasswordPolicy(policy, newPassword)
+	if message == "" {
+		isBreached, err := password.IsBreached(usecase.config.BREACHED_PASSWORD_DIR, newPassword)
+		if err != nil {
+			logger.LogError(ctx, "Error IsBreached", []zap.Field{
+				zap.Error(err),
+				zap.Strings("tags", []string{"usecase", "validatePassword"}),
+			}...)
+			return lib.ErrorInternalServer
+		}
+		if isBreached {
+			message = "password has appeared in a data breach"
+		}
+	}
+
+	if message == "" && user.ID > 0 && policy.HistoryDepth > 0 {
+		isReused, err := usecase.isPasswordReused(ctx, user, newPassword, policy.HistoryDepth)
+		if err != nil {
+			return err
+		}
+		if isReused {
+			message = fmt.Sprintf("must not be one of the last %d passwords", policy.HistoryDepth)
+		}
+	}
+
+	if message == "" {
+		return nil
+	}
+
+	validationError := lib.ErrorValidation
+	validationError.ErrDetails = map[string]any{
+		field: message,
+	}
+	return validationError
+}
+
+func checkPasswordPolicy(policy passwordPolicy, newPassword string) string {
+	if uint(len([]rune(newPassword))) < policy.MinLength {
+		return fmt.Sprintf("the length must be no less than %d", policy.MinLength)
+	}
+
+	hasUppercase, hasLowercase, hasNumber, hasSpecial := false, false, false, false
+	for _, char := range newPassword {
+		switch {
+		case unicode.IsUpper(char):
+			hasUppercase = true
+		case unicode.IsLower(char):
+			hasLowercase = true
+		case unicode.IsDigit(char):
+			hasNumber = true
+		case unicode.IsPunct(char) || unicode.IsSymbol(char):
+			hasSpecial = true
+		}
+	}
+
+	if policy.RequireUppercase && !hasUppercase {
+		return "at least one uppercase letter"
+	}
+	if policy.RequireLowercase && !hasLowercase {
+		return "at least one lowercase letter"
+	}
+	if policy.RequireNumber && !hasNumber {
+		return "at least one number"
+	}
+	if policy.RequireSpecial && !hasSpecial {
+		return "at least one special character"
+	}
+
+	return ""
+}
+
+// isPasswordReused compare the password with the current password and the recent password histories of the user
+func (usecase *Usecase) isPasswordReused(ctx context.Context, user model.User, newPassword string, historyDepth uint) (bool, error) {
+	if user.EncryptedPassword != "" && lib.CompareHashAndPassword(user.EncryptedPassword, newPassword) == nil {
+		return true, nil
+	}
+
+	userPasswordHistories, err := usecase.repo.GetUserPasswordHistories(ctx, request.GetUserPasswordHistories{
+		UserID: user.ID,
+		Limit:  int(historyDepth),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	for _, userPasswordHistory := range userPasswordHistories {
+		if lib.CompareHashAndPassword(userPasswordHistory.EncryptedPassword, newPassword) == nil {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// generatePasswordHash hash the password with the configured bcrypt cost
+func (usecase *Usecase) generatePasswordHash(ctx context.Context, str string) (string, error) {
+	hash, err := lib.GeneratePasswordHash(str, usecase.config.BCRYPT_COST)
+	if err != nil {
+		logger.LogError(ctx, "Error GeneratePasswordHash", []zap.Field{
+			zap.Error(err),
+			zap.Strings("tags", []string{"usecase", "generatePasswordHash"}),
+		}...)
+		return "", lib.ErrorInternalServer
+	}
+	return hash, nil
+}
+
+// recordPasswordHistory store the new password of the user and only keep as many histories as the policy needs,
+// it must be called after the user is saved with the new password
+func (usecase *Usecase) recordPasswordHistory(ctx context.Context, user model.User) error {
+	historyDepth, err := usecase.getAppSettingUint(ctx, constant.AppSettingPasswordHistoryDepth)
+	if err != nil {
+		return err
+	}
+
+	_, err = usecase.repo.CreateUserPasswordHistory(ctx, model.UserPasswordHistory{
+		UserID:            user.ID,
+		EncryptedPassword: user.EncryptedPassword,
+		CreatedAt:         user.UpdatedAt,
+		UpdatedAt:         user.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	return usecase.repo.PruneUserPasswordHistories(ctx, user.ID, int(historyDepth))
+}
+
+// checkPasswordAge reject a password login when the password is older than the max age of the policy,
+// the user has to set a new password through the forgot password flow
+func (usecase *Usecase) checkPasswordAge(ctx context.Context, user model.User) error {
+	maxAge, err := usecase.getAppSettingUint(ctx, constant.AppSettingPasswordMaxAge)
+	if err != nil {
+		return err
+	}
+	if maxAge == 0 || user.PasswordChangedAt == nil {
+		return nil
+	}
+
+	if time.Since(*user.PasswordChangedAt) > time.Duration(maxAge)*24*time.Hour {
+		return lib.ErrorPasswordExpired
+	}
+
+	return nil
+}
+
+// rehashPassword transparently upgrade the stored hash after a successful password login when it was generated
+// with a lower cost than the configured one, a failure is only logged since the login itself succeeded
+func (usecase *Usecase) rehashPassword(ctx context.Context, user model.User, plainPassword string) {
+	if !lib.IsPasswordHashCostLower(user.EncryptedPassword, usecase.config.BCRYPT_COST) {
+		return
+	}
+
+	encryptedPassword, err := usecase.generatePasswordHash(ctx, plainPassword)
+	if err != nil {
+		return
+	}
+
+	err = usecase.repo.UpdateUserEncryptedPassword(ctx, user.ID, encryptedPassword)
+	if err != nil {
+		logger.LogError(ctx, "Error UpdateUserEncryptedPassword", []zap.Field{
+			zap.Error(err),
+			zap.Uint("user_id", user.ID),
+			zap.Strings("tags", []string{"usecase", "rehashPassword"}),
+		}...)
+	}
+}
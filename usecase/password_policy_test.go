@@ -0,0 +1,38 @@
+package usecase
+
+import "testing"
+
+func TestCheckPasswordPolicy(t *testing.T) {
+	strictPolicy := passwordPolicy{
+		MinLength:        8,
+		RequireUppercase: true,
+		RequireLowercase: true,
+		RequireNumber:    true,
+		RequireSpecial:   true,
+	}
+
+	tests := []struct {
+		name     string
+		policy   passwordPolicy
+		password string
+		want     string
+	}{
+		{name: "empty policy", policy: passwordPolicy{}, password: "a", want: ""},
+		{name: "too short", policy: strictPolicy, password: "Ab1!", want: "the length must be no less than 8"},
+		{name: "length counted in runes", policy: passwordPolicy{MinLength: 4}, password: "ééé", want: "the length must be no less than 4"},
+		{name: "missing uppercase", policy: strictPolicy, password: "abcdef1!", want: "at least one uppercase letter"},
+		{name: "missing lowercase", policy: strictPolicy, password: "ABCDEF1!", want: "at least one lowercase letter"},
+		{name: "missing number", policy: strictPolicy, password: "Abcdefg!", want: "at least one number"},
+		{name: "missing special", policy: strictPolicy, password: "Abcdefg1", want: "at least one special character"},
+		{name: "symbol is special", policy: strictPolicy, password: "Abcdef1+", want: ""},
+		{name: "valid", policy: strictPolicy, password: "Abcdef1!", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkPasswordPolicy(tt.policy, tt.password); got != tt.want {
+				t.Errorf("message = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"app/lib"
+	"app/lib/cache"
+	"app/lib/signoz"
+	"app/request"
+	"context"
+	"time"
+)
+
+// HitRateLimit count a request against the limiter and return ErrorRateLimit once the limit is exceeded,
+// the result is always returned so the caller can expose the limiter state
+func (usecase *Usecase) HitRateLimit(ctx context.Context, req request.HitRateLimit) (cache.RateLimitResult, error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.HitRateLimit")
+	defer span.Finish()
+
+	res, err := usecase.repo.HitRateLimit(ctx, req.LimiterName, req.Identifier, req.Limit, req.Window)
+	if err != nil {
+		return res, err
+	}
+	if !res.Allowed {
+		rateLimitError := lib.ErrorRateLimit
+		rateLimitError.ErrDetails = map[string]any{
+			"remaining_ttl": res.ResetAfter / time.Second,
+		}
+		return res, rateLimitError
+	}
+
+	return res, nil
+}
@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"app/lib/constant"
+	"app/model"
+	"app/request"
+	"context"
+	"slices"
+)
+
+// assignDefaultRole assign default role to newly created user, skipped when the default role is not seeded
+func (usecase *Usecase) assignDefaultRole(ctx context.Context, userId uint) error {
+	role, err := usecase.repo.GetRole(ctx, request.GetRole{
+		Slug: constant.RoleUser,
+	})
+	if err != nil {
+		return err
+	}
+	if role.ID == 0 {
+		return nil
+	}
+
+	_, err = usecase.repo.CreateUserRole(ctx, model.UserRole{
+		UserID: userId,
+		RoleID: role.ID,
+	})
+	return err
+}
+
+// getUserRolesAndPermissions get user role slugs and its distinct permission slugs
+func (usecase *Usecase) getUserRolesAndPermissions(ctx context.Context, userId uint) (roles []string, permissions []string, err error) {
+	userRoles, err := usecase.repo.GetUserRoles(ctx, userId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	roles = []string{}
+	permissions = []string{}
+	for _, role := range userRoles {
+		roles = append(roles, role.Slug)
+		for _, permission := range role.Permissions {
+			if !slices.Contains(permissions, permission.Slug) {
+				permissions = append(permissions, permission.Slug)
+			}
+		}
+	}
+
+	return roles, permissions, nil
+}
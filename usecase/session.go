@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"app/lib"
+	"app/lib/auth"
+	"app/lib/constant"
+	"app/lib/logger"
+	"app/lib/signoz"
+	"app/model"
+	"app/request"
+	"app/response"
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func (usecase *Usecase) GetSessions(ctx context.Context, req request.GetSessions) (res []response.Session, err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.GetSessions")
+	defer span.Finish()
+
+	isActive := true
+	isRotated := false
+	auths, err := usecase.repo.GetAuths(ctx, request.GetAuths{
+		UserID:    req.UserID,
+		IsActive:  &isActive,
+		IsRotated: &isRotated,
+	})
+	if err != nil {
+		return res, err
+	}
+
+	res = []response.Session{}
+	for _, auth := range auths {
+		res = append(res, response.NewSession(auth, auth.AccessToken == req.AccessToken))
+	}
+	return res, nil
+}
+
+func (usecase *Usecase) RevokeSession(ctx context.Context, req request.RevokeSession) error {
+	ctx, span := signoz.StartSpan(ctx, "usecase.RevokeSession")
+	defer span.Finish()
+
+	auth, err := usecase.repo.GetAuth(ctx, request.GetAuth{
+		ID:     req.ID,
+		UserID: req.UserID,
+	})
+	if err != nil {
+		return err
+	}
+	if auth.ID == 0 {
+		notFoundError := lib.ErrorNotFound
+		notFoundError.Message = "Session Not Found"
+		return notFoundError
+	}
+
+	return usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := usecase.revokeAuthFamily(ctx, auth)
+		if err != nil {
+			return err
+		}
+
+		return usecase.recordAuditLog(ctx, model.AuditLog{
+			Action:     model.AuditLogActionSessionRevoke,
+			TargetType: model.AuditLogTargetUser,
+			TargetID:   auth.UserID,
+		}, nil, nil)
+	})
+}
+
+// Logout invalidate the current session, the access token is removed from cache and the session tokens are expired
+func (usecase *Usecase) Logout(ctx context.Context, req request.Logout) error {
+	ctx, span := signoz.StartSpan(ctx, "usecase.Logout")
+	defer span.Finish()
+
+	auth, err := usecase.repo.GetAuth(ctx, request.GetAuth{
+		UserID:      req.UserID,
+		AccessToken: req.AccessToken,
+	})
+	if err != nil {
+		return err
+	}
+
+	return usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		if auth.ID > 0 {
+			timeNow := time.Now()
+			auth.AccessTokenExpiredAt = timeNow
+			auth.RefreshTokenExpiredAt = timeNow
+			auth.IDTokenExpiredAt = timeNow
+			auth.UpdatedAt = timeNow
+			_, err := usecase.repo.UpdateAuth(ctx, auth)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := usecase.repo.DelAccessTokens(ctx, req.AccessToken)
+		if err != nil {
+			return err
+		}
+
+		if req.ClearMfaFlag {
+			return usecase.repo.DelMfaFlag(ctx, req.UserID)
+		}
+
+		return nil
+	})
+}
+
+func (usecase *Usecase) LogoutAll(ctx context.Context, req request.LogoutAll) error {
+	ctx, span := signoz.StartSpan(ctx, "usecase.LogoutAll")
+	defer span.Finish()
+
+	auths, err := usecase.repo.GetAuths(ctx, request.GetAuths{
+		UserID: req.UserID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := usecase.revokeAuths(ctx, auths)
+		if err != nil {
+			return err
+		}
+
+		return usecase.recordAuditLog(ctx, model.AuditLog{
+			Action:     model.AuditLogActionLogoutAll,
+			TargetType: model.AuditLogTargetUser,
+			TargetID:   req.UserID,
+		}, nil, nil)
+	})
+}
+
+// TouchSession update last_used_at of the session owning the access token, failure is only logged
+// because it must not block the request. A short lived redis flag skip the update for sessions touched recently
+func (usecase *Usecase) TouchSession(ctx context.Context, accessToken string) {
+	isSet, err := usecase.repo.SetSessionTouchedFlag(ctx, accessToken)
+	if err == nil && !isSet {
+		return
+	}
+
+	timeNow := time.Now()
+	threshold := timeNow.Add(-constant.SessionLastUsedInterval * time.Second)
+	err = usecase.repo.UpdateAuthLastUsedAt(ctx, accessToken, timeNow, threshold)
+	if err != nil {
+		logger.LogError(ctx, "Error UpdateAuthLastUsedAt", []zap.Field{
+			zap.Error(err),
+			zap.Strings("tags", []string{"usecase", "TouchSession"}),
+		}...)
+	}
+}
+
+// revokeAuths remove the access tokens from cache and soft delete the user_auths rows
+func (usecase *Usecase) revokeAuths(ctx context.Context, auths []model.UserAuth) error {
+	if len(auths) == 0 {
+		return nil
+	}
+
+	ids := []uint{}
+	accessTokens := []string{}
+	for _, auth := range auths {
+		ids = append(ids, auth.ID)
+		accessTokens = append(accessTokens, auth.AccessToken)
+	}
+
+	err := usecase.repo.DeleteAuths(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	return usecase.repo.DelAccessTokens(ctx, accessTokens...)
+}
+
+// revokeAuthFamily revoke every session sharing the refresh token family of the session, including the rotated ones.
+// A session without family only revoke itself, an empty family filter would match every session
+func (usecase *Usecase) revokeAuthFamily(ctx context.Context, userAuth model.UserAuth) error {
+	if userAuth.FamilyID == "" {
+		return usecase.revokeAuths(ctx, []model.UserAuth{userAuth})
+	}
+
+	auths, err := usecase.repo.GetAuths(ctx, request.GetAuths{
+		FamilyID: userAuth.FamilyID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return usecase.revokeAuths(ctx, auths)
+}
+
+// errRefreshTokenReused is returned when the refresh token was rotated by another request in the meantime
+var errRefreshTokenReused = errors.New("refresh token reused")
+
+// handleRefreshTokenReuse is called when a superseded refresh token is presented again,
+// the token family is considered compromised so the whole family is revoked
+func (usecase *Usecase) handleRefreshTokenReuse(ctx context.Context, userAuth model.UserAuth) error {
+	device := auth.GetDeviceFromCtx(ctx)
+	logger.LogError(ctx, "Security alert: refresh token reuse detected", []zap.Field{
+		zap.Uint("user_id", userAuth.UserID),
+		zap.Uint("auth_id", userAuth.ID),
+		zap.String("family_id", userAuth.FamilyID),
+		zap.String("ip_address", device.IPAddress),
+		zap.String("user_agent", device.UserAgent),
+		zap.Strings("tags", []string{"security", "usecase", "RefreshSession"}),
+	}...)
+
+	return usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		return usecase.revokeAuthFamily(ctx, userAuth)
+	})
+}
+
+// setAuthDevice fill the session device metadata from the request context
+func setAuthDevice(ctx context.Context, userAuth model.UserAuth) model.UserAuth {
+	device := auth.GetDeviceFromCtx(ctx)
+	timeNow := time.Now()
+
+	userAuth.UserAgent = device.UserAgent
+	userAuth.IPAddress = device.IPAddress
+	userAuth.LastUsedAt = &timeNow
+	return userAuth
+}
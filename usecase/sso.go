@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"app/lib"
+	"app/lib/oidc"
+	"app/lib/signoz"
+	"app/model"
+	"app/request"
+	"app/response"
+	"context"
+	"time"
+)
+
+// SsoLogin authenticate a user with an id token issued by one of the configured oidc providers,
+// the external identity is linked to an existing account or a newly registered one on first login
+func (usecase *Usecase) SsoLogin(ctx context.Context, req request.SsoLogin) (res response.Auth, err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.SsoLogin")
+	defer span.Finish()
+
+	claims, err := usecase.repo.VerifyOidcIDToken(ctx, req.Provider, req.IdToken)
+	if err != nil {
+		return res, err
+	}
+
+	userIdentity, err := usecase.repo.GetUserIdentity(ctx, request.GetUserIdentity{
+		Provider: req.Provider,
+		Subject:  claims.Subject,
+	})
+	if err != nil {
+		return res, err
+	}
+
+	var user model.User
+	if userIdentity.ID != 0 {
+		user, err = usecase.repo.GetUser(ctx, request.GetUser{
+			ID: userIdentity.UserID,
+		})
+		if err != nil {
+			return res, err
+		}
+		if user.ID == 0 {
+			return res, lib.ErrorUnauthorized
+		}
+	} else {
+		err = usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+			user, err = usecase.linkUserIdentity(ctx, req.Provider, claims)
+			return err
+		})
+		if err != nil {
+			return res, err
+		}
+	}
+
+	// Generate auth for register & login
+	isNeedMfa, err := usecase.isNeedMfa(ctx, user.ID)
+	if err != nil {
+		return res, err
+	}
+
+	var auth model.UserAuth
+	err = usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		auth, _, err = usecase.generateAuth(ctx, user, isNeedMfa)
+		if err != nil {
+			return err
+		}
+
+		return usecase.recordAuditLog(ctx, model.AuditLog{
+			ActorID:    user.ID,
+			Action:     model.AuditLogActionLogin,
+			TargetType: model.AuditLogTargetUser,
+			TargetID:   user.ID,
+		}, nil, nil)
+	})
+	if err != nil {
+		return res, err
+	}
+
+	return response.NewAuth(auth, user, isNeedMfa), nil
+}
+
+// linkUserIdentity attach the external identity to the user owning the same email, or register a new user.
+// An existing account is only matched when the provider has verified the email, otherwise anyone able to
+// register the email at the provider could take the account over
+func (usecase *Usecase) linkUserIdentity(ctx context.Context, provider string, claims *oidc.Claims) (user model.User, err error) {
+	if claims.Email == "" {
+		unauthorizedError := lib.ErrorUnauthorized
+		unauthorizedError.Message = "Email Not Provided By SSO Provider"
+		return user, unauthorizedError
+	}
+
+	user, err = usecase.repo.GetUser(ctx, request.GetUser{
+		Email: claims.Email,
+	})
+	if err != nil {
+		return user, err
+	}
+
+	timeNow := time.Now()
+	if user.ID != 0 && !claims.IsEmailVerified() {
+		unauthorizedError := lib.ErrorUnauthorized
+		unauthorizedError.Message = "Email Not Verified By SSO Provider"
+		return user, unauthorizedError
+	}
+	if user.ID == 0 {
+		// Register
+		name := claims.Name
+		if name == "" {
+			name = claims.Email
+		}
+
+		user, err = usecase.repo.CreateUser(ctx, model.User{
+			Name:       name,
+			Email:      claims.Email,
+			IsActive:   true,
+			IsVerified: claims.IsEmailVerified(),
+			CreatedAt:  timeNow,
+			UpdatedAt:  timeNow,
+		})
+		if err != nil {
+			return user, err
+		}
+
+		err = usecase.assignDefaultRole(ctx, user.ID)
+		if err != nil {
+			return user, err
+		}
+	}
+
+	_, err = usecase.repo.CreateUserIdentity(ctx, model.UserIdentity{
+		UserID:    user.ID,
+		Provider:  provider,
+		Subject:   claims.Subject,
+		Email:     claims.Email,
+		CreatedAt: timeNow,
+		UpdatedAt: timeNow,
+	})
+	if err != nil {
+		return user, err
+	}
+
+	return user, nil
+}
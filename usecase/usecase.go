@@ -2,20 +2,27 @@ package usecase
 
 import (
 	"app/config"
+	"app/lib/auth"
 	"app/lib/storage"
 	"app/repository"
+
+	"github.com/go-webauthn/webauthn/webauthn"
 )
 
 type Usecase struct {
-	config  *config.Config
-	repo    *repository.Repository
-	storage storage.Storage
+	config   *config.Config
+	repo     *repository.Repository
+	storage  storage.Storage
+	keySet   *auth.KeySet
+	webAuthn *webauthn.WebAuthn
 }
 
-func NewUsecase(config *config.Config, repo *repository.Repository, storage storage.Storage) Usecase {
+func NewUsecase(config *config.Config, repo *repository.Repository, storage storage.Storage, keySet *auth.KeySet, webAuthn *webauthn.WebAuthn) Usecase {
 	return Usecase{
-		config:  config,
-		repo:    repo,
-		storage: storage,
+		config:   config,
+		repo:     repo,
+		storage:  storage,
+		keySet:   keySet,
+		webAuthn: webAuthn,
 	}
 }
@@ -2,21 +2,22 @@ package usecase
 
 import (
 	"app/lib"
-	"app/lib/logger"
 	"app/lib/signoz"
 	"app/model"
 	"app/request"
 	"app/response"
 	"context"
 	"time"
-
-	"go.uber.org/zap"
 )
 
 func (usecase *Usecase) GetUsers(ctx context.Context, req request.GetUsers) (res response.GetUsers, err error) {
 	ctx, span := signoz.StartSpan(ctx, "usecase.GetUsers")
 	defer span.Finish()
 
+	if req.IsCursorPagination() {
+		return usecase.getUsersByCursor(ctx, req)
+	}
+
 	users, total, err := usecase.repo.GetUsers(ctx, req)
 	if err != nil {
 		return res, err
@@ -30,6 +31,64 @@ func (usecase *Usecase) GetUsers(ctx context.Context, req request.GetUsers) (res
 	return res, nil
 }
 
+func (usecase *Usecase) getUsersByCursor(ctx context.Context, req request.GetUsers) (res response.GetUsers, err error) {
+	users, total, hasMore, err := usecase.repo.GetUsersByCursor(ctx, req)
+	if err != nil {
+		return res, err
+	}
+
+	cursorQuery, err := req.GetCursorQuery()
+	if err != nil {
+		return res, err
+	}
+
+	res.Data = []response.UserList{}
+	for _, user := range users {
+		res.Data = append(res.Data, response.NewUserList(user))
+	}
+	res.IsCursor = true
+	res.HasTotal = req.WithTotal
+	res.Total = uint(total)
+	res.Limit = req.GetCursorLimit()
+	if len(users) == 0 {
+		return res, nil
+	}
+
+	// Walking forward there is a previous page unless this is the first one, walking backward there is always a next page
+	hasNext, hasPrev := hasMore, req.Cursor != ""
+	if cursorQuery.IsPrev {
+		hasNext, hasPrev = true, hasMore
+	}
+
+	if hasNext {
+		res.NextCursor = request.EncodeCursor(newUserCursor(cursorQuery.SortKey, users[len(users)-1], false))
+	}
+	if hasPrev {
+		res.PrevCursor = request.EncodeCursor(newUserCursor(cursorQuery.SortKey, users[0], true))
+	}
+	return res, nil
+}
+
+func newUserCursor(sortKey string, user model.User, isPrev bool) request.Cursor {
+	cursor := request.Cursor{
+		Sort:   sortKey,
+		ID:     user.ID,
+		IsPrev: isPrev,
+	}
+
+	switch sortKey {
+	case "name":
+		cursor.Value = user.Name
+	case "email":
+		cursor.Value = user.Email
+	case "created_at":
+		cursor.Value = user.CreatedAt
+	case "updated_at":
+		cursor.Value = user.UpdatedAt
+	}
+	return cursor
+}
+
 func (usecase *Usecase) GetUser(ctx context.Context, req request.GetUser) (res response.UserDetailed, err error) {
 	ctx, span := signoz.StartSpan(ctx, "usecase.GetUser")
 	defer span.Finish()
@@ -65,27 +124,56 @@ func (usecase *Usecase) CreateUser(ctx context.Context, req request.CreateUser)
 		return validationError
 	}
 
-	encryptedPassword, err := lib.GeneratePasswordHash(req.Password)
+	err = usecase.validatePassword(ctx, "password", req.Password, model.User{})
+	if err != nil {
+		return err
+	}
+
+	encryptedPassword, err := usecase.generatePasswordHash(ctx, req.Password)
 	if err != nil {
-		logger.LogError(ctx, "Error GeneratePasswordHash", []zap.Field{
-			zap.Error(err),
-			zap.Strings("tags", []string{"usecase", "user"}),
-		}...)
-		return lib.ErrorInternalServer
+		return err
 	}
 
+	return usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		return usecase.createVerifiedUser(ctx, req, encryptedPassword, 0)
+	})
+}
+
+// createVerifiedUser create an active and verified user with the default role, used by the admin and the bulk import.
+// The creation is audited with the given actor, or the authenticated user when it is 0
+func (usecase *Usecase) createVerifiedUser(ctx context.Context, req request.CreateUser, encryptedPassword string, actorId uint) error {
 	timeNow := time.Now()
-	_, err = usecase.repo.CreateUser(ctx, model.User{
+	user, err := usecase.repo.CreateUser(ctx, model.User{
 		Name:              req.Name,
 		Email:             req.Email,
 		PhoneNumber:       req.PhoneNumber,
 		EncryptedPassword: encryptedPassword,
 		IsActive:          true,
 		IsVerified:        true,
+		PasswordChangedAt: &timeNow,
 		CreatedAt:         timeNow,
 		UpdatedAt:         timeNow,
 	})
-	return err
+	if err != nil {
+		return err
+	}
+
+	err = usecase.recordPasswordHistory(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	err = usecase.recordAuditLog(ctx, model.AuditLog{
+		ActorID:    actorId,
+		Action:     model.AuditLogActionUserCreate,
+		TargetType: model.AuditLogTargetUser,
+		TargetID:   user.ID,
+	}, model.User{}, user)
+	if err != nil {
+		return err
+	}
+
+	return usecase.assignDefaultRole(ctx, user.ID)
 }
 
 func (usecase *Usecase) UpdateUser(ctx context.Context, req request.UpdateUser) (err error) {
@@ -120,18 +208,40 @@ func (usecase *Usecase) UpdateUser(ctx context.Context, req request.UpdateUser)
 		}
 	}
 
-	timeNow := time.Now()
-	user.Name = req.Name
-	user.Email = req.Email
-	user.PhoneNumber = req.PhoneNumber
-	user.UpdatedAt = timeNow
-	_, err = usecase.repo.UpdateUser(ctx, user)
-	return err
+	return usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		before := user
+		user.Name = req.Name
+		user.Email = req.Email
+		user.PhoneNumber = req.PhoneNumber
+		user.UpdatedAt = time.Now()
+		_, err := usecase.repo.UpdateUser(ctx, user)
+		if err != nil {
+			return err
+		}
+
+		return usecase.recordAuditLog(ctx, model.AuditLog{
+			Action:     model.AuditLogActionUserUpdate,
+			TargetType: model.AuditLogTargetUser,
+			TargetID:   user.ID,
+		}, before, user)
+	})
 }
 
 func (usecase *Usecase) DeleteUser(ctx context.Context, req request.DeleteUser) (err error) {
 	ctx, span := signoz.StartSpan(ctx, "usecase.DeleteUser")
 	defer span.Finish()
 
-	return usecase.repo.DeleteUser(ctx, req.ID)
+	user, err := usecase.repo.GetUser(ctx, request.GetUser{
+		ID: req.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if user.ID == 0 {
+		notFoundError := lib.ErrorNotFound
+		notFoundError.Message = "User Not Found"
+		return notFoundError
+	}
+
+	return usecase.deleteUser(ctx, user)
 }
@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"app/lib"
+	"app/lib/constant"
+	"app/lib/logger"
+	"app/lib/signoz"
+	"app/model"
+	"app/request"
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DeactivateUser disable the user and revoke every session, the user can no longer sign in until reactivated
+func (usecase *Usecase) DeactivateUser(ctx context.Context, req request.DeactivateUser) (err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.DeactivateUser")
+	defer span.Finish()
+
+	user, err := usecase.repo.GetUser(ctx, request.GetUser{
+		ID: req.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if user.ID == 0 {
+		notFoundError := lib.ErrorNotFound
+		notFoundError.Message = "User Not Found"
+		return notFoundError
+	}
+
+	auths, err := usecase.repo.GetAuths(ctx, request.GetAuths{
+		UserID: user.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		before := user
+		user.IsActive = false
+		user.UpdatedAt = time.Now()
+		_, err := usecase.repo.UpdateUser(ctx, user)
+		if err != nil {
+			return err
+		}
+
+		err = usecase.recordAuditLog(ctx, model.AuditLog{
+			Action:     model.AuditLogActionUserDeactivate,
+			TargetType: model.AuditLogTargetUser,
+			TargetID:   user.ID,
+		}, before, user)
+		if err != nil {
+			return err
+		}
+
+		err = usecase.revokeAuths(ctx, auths)
+		if err != nil {
+			return err
+		}
+
+		return usecase.repo.DelMfaFlag(ctx, user.ID)
+	})
+}
+
+func (usecase *Usecase) ReactivateUser(ctx context.Context, req request.ReactivateUser) (err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.ReactivateUser")
+	defer span.Finish()
+
+	user, err := usecase.repo.GetUser(ctx, request.GetUser{
+		ID: req.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if user.ID == 0 {
+		notFoundError := lib.ErrorNotFound
+		notFoundError.Message = "User Not Found"
+		return notFoundError
+	}
+
+	return usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		before := user
+		user.IsActive = true
+		user.UpdatedAt = time.Now()
+		_, err := usecase.repo.UpdateUser(ctx, user)
+		if err != nil {
+			return err
+		}
+
+		return usecase.recordAuditLog(ctx, model.AuditLog{
+			Action:     model.AuditLogActionUserReactivate,
+			TargetType: model.AuditLogTargetUser,
+			TargetID:   user.ID,
+		}, before, user)
+	})
+}
+
+// EraseUser permanently erase the personal data of a deleted user: every record referencing the user is removed,
+// the user row and its audit logs are anonymised and the uploaded files are removed from the storage. It is safe to run more than once
+func (usecase *Usecase) EraseUser(ctx context.Context, payload request.EraseUserPayload) (err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.EraseUser")
+	defer span.Finish()
+
+	user, err := usecase.repo.GetUser(ctx, request.GetUser{
+		ID:          payload.UserID,
+		WithDeleted: true,
+	})
+	if err != nil {
+		return err
+	}
+	if user.ID == 0 || !user.DeletedAt.Valid {
+		logger.LogInfo(ctx, "Skip erase user, user not found or not deleted", []zap.Field{
+			zap.Uint("user_id", payload.UserID),
+			zap.Strings("tags", []string{"usecase", "EraseUser"}),
+		}...)
+		return nil
+	}
+
+	auths, err := usecase.repo.GetAuths(ctx, request.GetAuths{
+		UserID: user.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	accessTokens := []string{}
+	for _, auth := range auths {
+		accessTokens = append(accessTokens, auth.AccessToken)
+	}
+	err = usecase.repo.DelAccessTokens(ctx, accessTokens...)
+	if err != nil {
+		return err
+	}
+
+	if user.AvatarPath != "" {
+		err = usecase.storage.RemoveFile(ctx, "", user.AvatarPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The jobs of the user are deleted with the other relations below
+	err = usecase.removeUserTransferFiles(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	err = usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := usecase.repo.DeleteUserRelations(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+
+		err = usecase.repo.ScrubUserAuditLogs(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+
+		return usecase.repo.EraseUser(ctx, user.ID, fmt.Sprintf("erased-%d@erased.invalid", user.ID))
+	})
+	if err != nil {
+		return err
+	}
+
+	err = usecase.repo.DelMfaFlag(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	return usecase.resetLoginFailure(ctx, user.Email)
+}
+
+// deleteUser soft delete the user, revoke every session and schedule the erasure of the personal data
+func (usecase *Usecase) deleteUser(ctx context.Context, user model.User) error {
+	auths, err := usecase.repo.GetAuths(ctx, request.GetAuths{
+		UserID: user.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := usecase.revokeAuths(ctx, auths)
+		if err != nil {
+			return err
+		}
+
+		err = usecase.repo.DeleteUser(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+
+		err = usecase.recordAuditLog(ctx, model.AuditLog{
+			Action:     model.AuditLogActionUserDelete,
+			TargetType: model.AuditLogTargetUser,
+			TargetID:   user.ID,
+		}, nil, nil)
+		if err != nil {
+			return err
+		}
+
+		return usecase.repo.DelMfaFlag(ctx, user.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Published after the commit, otherwise the worker could see the user not deleted yet and skip the erasure
+	return usecase.repo.PublishTask(ctx, constant.TaskTypeUserErase, request.EraseUserPayload{
+		UserID: user.ID,
+	})
+}
+
+// checkUserActive reject a deactivated user from starting or refreshing a session
+func checkUserActive(user model.User) error {
+	if !user.IsActive {
+		return lib.ErrorAccountInactive
+	}
+	return nil
+}
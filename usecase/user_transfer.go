@@ -0,0 +1,509 @@
+package usecase
+
+import (
+	"app/lib"
+	"app/lib/constant"
+	"app/lib/logger"
+	"app/lib/signoz"
+	"app/lib/spreadsheet"
+	"app/model"
+	"app/request"
+	"app/response"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	userImportColumns = []string{"name", "email", "phone_number", "password"}
+	userExportColumns = []string{"id", "name", "email", "phone_number", "is_active", "is_verified", "created_at"}
+)
+
+type userImportError struct {
+	Row     int
+	Field   string
+	Message string
+}
+
+// ImportUsers store the uploaded file and publish the import job, the transfer can be polled with GetUserTransfer
+// or GetJob since the transfer id is the job id
+func (usecase *Usecase) ImportUsers(ctx context.Context, req request.ImportUsers) (res response.UserTransfer, err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.ImportUsers")
+	defer span.Finish()
+
+	uploadedFile, err := usecase.UploadFile(ctx, request.UploadFile{
+		FileData:   req.FileData,
+		TargetPath: constant.UploadPathUserImport,
+	})
+	if err != nil {
+		return res, err
+	}
+
+	return usecase.publishUserTransfer(ctx, constant.TaskTypeUserImport, req.UserID, model.UserTransfer{
+		Type:       model.UserTransferTypeImport,
+		Format:     req.FileExtension,
+		SourcePath: uploadedFile.Filepath,
+	}, request.ImportUsersPayload{
+		Format:     req.FileExtension,
+		SourcePath: uploadedFile.Filepath,
+		UserID:     req.UserID,
+	})
+}
+
+// ExportUsers publish the export job of the users matching the search and filters
+func (usecase *Usecase) ExportUsers(ctx context.Context, req request.ExportUsers) (res response.UserTransfer, err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.ExportUsers")
+	defer span.Finish()
+
+	return usecase.publishUserTransfer(ctx, constant.TaskTypeUserExport, req.UserID, model.UserTransfer{
+		Type:   model.UserTransferTypeExport,
+		Format: req.Format,
+	}, request.ExportUsersPayload{
+		Format:  req.Format,
+		Search:  req.Search,
+		Sort:    req.Sort,
+		Filters: req.Filters,
+	})
+}
+
+func (usecase *Usecase) GetUserTransfer(ctx context.Context, req request.GetUserTransfer) (res response.UserTransfer, err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.GetUserTransfer")
+	defer span.Finish()
+
+	job, err := usecase.repo.GetJob(ctx, req.ID)
+	if err != nil {
+		return res, err
+	}
+	if job.ID == "" || (job.Type != constant.TaskTypeUserImport && job.Type != constant.TaskTypeUserExport) {
+		notFoundError := lib.ErrorNotFound
+		notFoundError.Message = "User Transfer Not Found"
+		return res, notFoundError
+	}
+
+	userTransfer := model.UserTransfer{}
+	if job.Result != "" {
+		err = json.Unmarshal([]byte(job.Result), &userTransfer)
+		if err != nil {
+			return res, err
+		}
+	}
+
+	resultURL := ""
+	if userTransfer.ResultPath != "" {
+		resultURL, err = usecase.storage.GetFileTemporaryURL(ctx, "", userTransfer.ResultPath)
+		if err != nil {
+			return res, err
+		}
+	}
+
+	return response.NewUserTransfer(job, userTransfer, resultURL), nil
+}
+
+// ProcessImportUsers validate every row with the CreateUser rules and create the valid users in batches,
+// invalid rows are written into an error report. The job result is updated after every batch
+func (usecase *Usecase) ProcessImportUsers(ctx context.Context, payload request.ImportUsersPayload) (err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.ProcessImportUsers")
+	defer span.Finish()
+
+	userTransfer := model.UserTransfer{
+		Type:       model.UserTransferTypeImport,
+		Format:     payload.Format,
+		SourcePath: payload.SourcePath,
+	}
+	err = usecase.importUsers(ctx, &userTransfer, payload)
+	return usecase.finishUserTransfer(ctx, userTransfer, err)
+}
+
+// ProcessExportUsers write the users matching the filters page by page into a file and store it,
+// the pages are read with cursor pagination so large tables are not scanned with offset
+func (usecase *Usecase) ProcessExportUsers(ctx context.Context, payload request.ExportUsersPayload) (err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.ProcessExportUsers")
+	defer span.Finish()
+
+	userTransfer := model.UserTransfer{
+		Type:   model.UserTransferTypeExport,
+		Format: payload.Format,
+	}
+	err = usecase.exportUsers(ctx, &userTransfer, payload)
+	return usecase.finishUserTransfer(ctx, userTransfer, err)
+}
+
+func (usecase *Usecase) importUsers(ctx context.Context, userTransfer *model.UserTransfer, payload request.ImportUsersPayload) error {
+	reader, err := usecase.storage.GetObject(ctx, "", payload.SourcePath)
+	if err != nil {
+		return err
+	}
+
+	rows, err := spreadsheet.ReadRows(userTransfer.Format, reader)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf("file is empty")
+	}
+
+	columnIndexes := map[string]int{}
+	for i, column := range rows[0] {
+		columnIndexes[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	for _, column := range userImportColumns {
+		if _, ok := columnIndexes[column]; !ok {
+			return fmt.Errorf("missing column %s", column)
+		}
+	}
+
+	policy, err := usecase.getPasswordPolicy(ctx)
+	if err != nil {
+		return err
+	}
+
+	rows = rows[1:]
+	userTransfer.Total = len(rows)
+	importErrors := []userImportError{}
+	seenEmails := map[string]bool{}
+	batchSize := max(usecase.config.USER_TRANSFER_BATCH_SIZE, 1)
+	for start := 0; start < len(rows); start += batchSize {
+		end := min(start+batchSize, len(rows))
+
+		createUsers := []request.CreateUser{}
+		encryptedPasswords := []string{}
+		createRowNumbers := []int{}
+		for i, row := range rows[start:end] {
+			// The header is the first row of the file, so the data starts at the second row
+			rowNumber := start + i + 2
+			req := request.CreateUser{
+				Name:        getRowColumn(row, columnIndexes["name"]),
+				Email:       getRowColumn(row, columnIndexes["email"]),
+				PhoneNumber: getRowColumn(row, columnIndexes["phone_number"]),
+				Password:    getRowColumn(row, columnIndexes["password"]),
+			}
+
+			rowErrors, err := usecase.validateUserImportRow(ctx, policy, req, seenEmails)
+			if err != nil {
+				return err
+			}
+			seenEmails[strings.ToLower(req.Email)] = true
+			if len(rowErrors) > 0 {
+				for _, field := range slices.Sorted(maps.Keys(rowErrors)) {
+					importErrors = append(importErrors, userImportError{Row: rowNumber, Field: field, Message: fmt.Sprint(rowErrors[field])})
+				}
+				userTransfer.Failed++
+				continue
+			}
+
+			encryptedPassword, err := usecase.generatePasswordHash(ctx, req.Password)
+			if err != nil {
+				return err
+			}
+			createUsers = append(createUsers, req)
+			encryptedPasswords = append(encryptedPasswords, encryptedPassword)
+			createRowNumbers = append(createRowNumbers, rowNumber)
+		}
+
+		err = usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+			for i, req := range createUsers {
+				err := usecase.createVerifiedUser(ctx, req, encryptedPasswords[i], payload.UserID)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			logger.LogError(ctx, "Error create import users batch", []zap.Field{
+				zap.Error(err),
+				zap.String("transfer_id", getUserTransferID(ctx)),
+				zap.Strings("tags", []string{"usecase", "importUsers"}),
+			}...)
+			for _, rowNumber := range createRowNumbers {
+				importErrors = append(importErrors, userImportError{Row: rowNumber, Message: "failed to create user"})
+			}
+			userTransfer.Failed += len(createUsers)
+		}
+
+		userTransfer.Processed = end
+		err = usecase.updateUserTransfer(ctx, *userTransfer)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(importErrors) == 0 {
+		return nil
+	}
+
+	reportPath := fmt.Sprintf("%s/%s.csv", constant.UploadPathUserImportReport, getUserTransferID(ctx))
+	err = usecase.writeUserImportReport(ctx, reportPath, importErrors)
+	if err != nil {
+		return err
+	}
+	userTransfer.ResultPath = reportPath
+	return nil
+}
+
+// validateUserImportRow return the validation errors of the row by field, emails already seen earlier in the file are rejected
+func (usecase *Usecase) validateUserImportRow(ctx context.Context, policy passwordPolicy, req request.CreateUser, seenEmails map[string]bool) (map[string]any, error) {
+	err := req.Validate()
+	if err != nil {
+		return getValidationErrDetails(err)
+	}
+
+	if seenEmails[strings.ToLower(req.Email)] {
+		return map[string]any{"email": "Email is duplicated in the file"}, nil
+	}
+
+	err = usecase.checkEmailAvailable(ctx, req.Email)
+	if err != nil {
+		return getValidationErrDetails(err)
+	}
+
+	err = usecase.validatePasswordWithPolicy(ctx, policy, "password", req.Password, model.User{})
+	if err != nil {
+		return getValidationErrDetails(err)
+	}
+
+	return nil, nil
+}
+
+func (usecase *Usecase) writeUserImportReport(ctx context.Context, reportPath string, importErrors []userImportError) error {
+	buf := bytes.Buffer{}
+	csvWriter := csv.NewWriter(&buf)
+	err := csvWriter.Write([]string{"row", "field", "message"})
+	if err != nil {
+		return err
+	}
+	for _, importError := range importErrors {
+		err = csvWriter.Write([]string{strconv.Itoa(importError.Row), importError.Field, importError.Message})
+		if err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	err = csvWriter.Error()
+	if err != nil {
+		return err
+	}
+
+	return usecase.storage.UploadFile(ctx, "", reportPath, "text/csv", &buf)
+}
+
+func (usecase *Usecase) exportUsers(ctx context.Context, userTransfer *model.UserTransfer, payload request.ExportUsersPayload) error {
+	transferId := getUserTransferID(ctx)
+	tmpPath := filepath.Join(os.TempDir(), fmt.Sprintf("%s.%s", transferId, userTransfer.Format))
+	defer os.Remove(tmpPath)
+
+	err := usecase.writeUserExportFile(ctx, userTransfer, payload, tmpPath)
+	if err != nil {
+		return err
+	}
+
+	resultPath := fmt.Sprintf("%s/%s.%s", constant.UploadPathUserExport, transferId, userTransfer.Format)
+	err = usecase.storage.FPutObject(ctx, "", resultPath, tmpPath)
+	if err != nil {
+		return err
+	}
+	userTransfer.ResultPath = resultPath
+	return nil
+}
+
+func (usecase *Usecase) writeUserExportFile(ctx context.Context, userTransfer *model.UserTransfer, payload request.ExportUsersPayload, path string) (err error) {
+	writer, err := spreadsheet.NewWriter(userTransfer.Format, path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := writer.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	err = writer.Write(userExportColumns)
+	if err != nil {
+		return err
+	}
+
+	req := request.GetUsers{
+		BasePaginateRequest: request.BasePaginateRequest{
+			Sort:       payload.Sort,
+			Search:     payload.Search,
+			Filters:    payload.Filters,
+			Pagination: request.PaginationCursor,
+			Limit:      uint(max(usecase.config.USER_TRANSFER_BATCH_SIZE, 1)),
+			WithTotal:  true,
+		},
+	}
+	cursorQuery, err := req.GetCursorQuery()
+	if err != nil {
+		return err
+	}
+
+	for {
+		users, total, hasMore, err := usecase.repo.GetUsersByCursor(ctx, req)
+		if err != nil {
+			return err
+		}
+		if req.WithTotal {
+			userTransfer.Total = int(total)
+			req.WithTotal = false
+		}
+
+		for _, user := range users {
+			err = writer.Write([]string{
+				strconv.Itoa(int(user.ID)),
+				spreadsheet.EscapeFormula(user.Name),
+				spreadsheet.EscapeFormula(user.Email),
+				spreadsheet.EscapeFormula(user.PhoneNumber),
+				strconv.FormatBool(user.IsActive),
+				strconv.FormatBool(user.IsVerified),
+				user.CreatedAt.Format(time.RFC3339),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		userTransfer.Processed += len(users)
+		err = usecase.updateUserTransfer(ctx, *userTransfer)
+		if err != nil {
+			return err
+		}
+
+		if !hasMore || len(users) == 0 {
+			return nil
+		}
+		req.Cursor = request.EncodeCursor(newUserCursor(cursorQuery.SortKey, users[len(users)-1], false))
+	}
+}
+
+// publishUserTransfer publish the transfer job with the initial transfer as its result
+func (usecase *Usecase) publishUserTransfer(ctx context.Context, taskType string, userId uint, userTransfer model.UserTransfer, payload any) (res response.UserTransfer, err error) {
+	resultBytes, err := json.Marshal(userTransfer)
+	if err != nil {
+		return res, err
+	}
+
+	timeNow := time.Now()
+	job, err := usecase.repo.PublishJob(ctx, model.Job{
+		Type:      taskType,
+		UserID:    userId,
+		Result:    string(resultBytes),
+		CreatedAt: timeNow,
+		UpdatedAt: timeNow,
+	}, payload)
+	if err != nil {
+		return res, err
+	}
+
+	return response.NewUserTransfer(job, userTransfer, ""), nil
+}
+
+// updateUserTransfer store the transfer as the result of the job processed in ctx, with its progress
+func (usecase *Usecase) updateUserTransfer(ctx context.Context, userTransfer model.UserTransfer) error {
+	job, err := usecase.getCurrentJob(ctx)
+	if err != nil || job.ID == "" {
+		return err
+	}
+
+	resultBytes, err := json.Marshal(userTransfer)
+	if err != nil {
+		return err
+	}
+
+	job.Result = string(resultBytes)
+	job.Progress = userTransfer.Progress()
+	return usecase.updateJob(ctx, job)
+}
+
+// finishUserTransfer store the final result of the transfer, the process error is returned so the worker fail the job.
+// The uploaded import file contains plaintext passwords, so it is removed whether the import succeeded or failed
+func (usecase *Usecase) finishUserTransfer(ctx context.Context, userTransfer model.UserTransfer, processErr error) error {
+	if userTransfer.SourcePath != "" {
+		err := usecase.storage.RemoveFile(ctx, "", userTransfer.SourcePath)
+		if err != nil {
+			logger.LogError(ctx, "Error remove user transfer source file", []zap.Field{
+				zap.Error(err),
+				zap.String("source_path", userTransfer.SourcePath),
+				zap.Strings("tags", []string{"usecase", "finishUserTransfer"}),
+			}...)
+		} else {
+			userTransfer.SourcePath = ""
+		}
+	}
+
+	err := usecase.updateUserTransfer(ctx, userTransfer)
+	if err != nil {
+		return err
+	}
+	return processErr
+}
+
+// removeUserTransferFiles remove the uploaded import files, the import error reports and the exported files
+// of the transfers started by the user
+func (usecase *Usecase) removeUserTransferFiles(ctx context.Context, userId uint) error {
+	jobs, err := usecase.repo.GetJobs(ctx, request.GetJobs{
+		UserID: userId,
+		Types:  []string{constant.TaskTypeUserImport, constant.TaskTypeUserExport},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		userTransfer := model.UserTransfer{}
+		if job.Result != "" {
+			err = json.Unmarshal([]byte(job.Result), &userTransfer)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, path := range []string{userTransfer.SourcePath, userTransfer.ResultPath} {
+			if path == "" {
+				continue
+			}
+			err = usecase.storage.RemoveFile(ctx, "", path)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// getUserTransferID get the id of the transfer processed in ctx, which is the id of its job
+func getUserTransferID(ctx context.Context) string {
+	transferId, _ := ctx.Value(logger.CtxProcessID).(string)
+	if transferId == "" {
+		return lib.GenerateUUID()
+	}
+	return transferId
+}
+
+func getRowColumn(row []string, index int) string {
+	if index >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[index])
+}
+
+// getValidationErrDetails return the details of a validation error, any other error is returned as is
+func getValidationErrDetails(err error) (map[string]any, error) {
+	customError, ok := err.(lib.CustomError)
+	if !ok || customError.Code != lib.ErrorValidation.Code {
+		return nil, err
+	}
+	return customError.ErrDetails, nil
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"app/lib"
+	"app/lib/logger"
+	"app/lib/signoz"
+	"app/model"
+	"app/request"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// CleanupUserVerifications permanently remove used or expired verification codes once they pass the retention period
+func (usecase *Usecase) CleanupUserVerifications(ctx context.Context) error {
+	ctx, span := signoz.StartSpan(ctx, "usecase.CleanupUserVerifications")
+	defer span.Finish()
+
+	threshold := time.Now().Add(-time.Duration(usecase.config.VERIFICATION_RETENTION) * time.Second)
+	deleted, err := usecase.repo.DeleteStaleUserVerifications(ctx, threshold)
+	if err != nil {
+		return err
+	}
+
+	logger.LogInfo(ctx, "Stale user verifications cleaned up", []zap.Field{
+		zap.Int64("deleted", deleted),
+		zap.Strings("tags", []string{"usecase", "CleanupUserVerifications"}),
+	}...)
+	return nil
+}
+
+// getActiveUserVerification get the unused and unexpired verification of the given type, if any
+func (usecase *Usecase) getActiveUserVerification(ctx context.Context, userId uint, verificationType string) (model.UserVerification, error) {
+	isUsed := false
+	isExpired := false
+	return usecase.repo.GetUserVerification(ctx, request.GetUserVerification{
+		Type:      verificationType,
+		UserID:    userId,
+		IsUsed:    &isUsed,
+		IsExpired: &isExpired,
+	})
+}
+
+// createUserVerification create a new verification code which expires after the ttl configured for its type,
+// only the type, user and target need to be filled by the caller
+func (usecase *Usecase) createUserVerification(ctx context.Context, userVerification model.UserVerification) (model.UserVerification, error) {
+	timeNow := time.Now()
+	expiredAt := timeNow.Add(usecase.getVerificationTtl(userVerification.Type))
+	userVerification.Code = lib.GenerateUUID()
+	userVerification.ExpiredAt = &expiredAt
+	userVerification.CreatedAt = timeNow
+	userVerification.UpdatedAt = timeNow
+	return usecase.repo.CreateUserVerification(ctx, userVerification)
+}
+
+func (usecase *Usecase) getVerificationTtl(verificationType string) time.Duration {
+	switch verificationType {
+	case model.UserVerificationTypeVerifyAccount:
+		return time.Duration(usecase.config.VERIFY_ACCOUNT_TTL) * time.Second
+	case model.UserVerificationTypeResetPassword:
+		return time.Duration(usecase.config.RESET_PASSWORD_TTL) * time.Second
+	case model.UserVerificationTypeMagicLogin:
+		return time.Duration(usecase.config.MAGIC_LINK_TTL) * time.Second
+	case model.UserVerificationTypeChangeEmail:
+		return time.Duration(usecase.config.CHANGE_EMAIL_TTL) * time.Second
+	}
+	return 0
+}
+
+// useUserVerification mark the verification as used, the verification is inactive when a concurrent request used it first
+func (usecase *Usecase) useUserVerification(ctx context.Context, userVerification model.UserVerification, usedAt time.Time) error {
+	isUsed, err := usecase.repo.UseUserVerification(ctx, userVerification.ID, usedAt)
+	if err != nil {
+		return err
+	}
+	if !isUsed {
+		return lib.ErrorVerificationInactive
+	}
+
+	return nil
+}
+
+// checkUserVerification reject a verification code that was already used or has expired
+func checkUserVerification(userVerification model.UserVerification) error {
+	if userVerification.IsUsed() {
+		return lib.ErrorVerificationInactive
+	}
+
+	if userVerification.IsExpired() {
+		return lib.ErrorVerificationExpired
+	}
+
+	return nil
+}
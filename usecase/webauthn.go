@@ -0,0 +1,539 @@
+package usecase
+
+import (
+	"app/lib"
+	"app/lib/constant"
+	"app/lib/logger"
+	"app/lib/signoz"
+	"app/model"
+	"app/request"
+	"app/response"
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"go.uber.org/zap"
+)
+
+// webauthnUser adapts a user and its stored credentials to the webauthn.User interface,
+// the user handle is the user id so a discoverable credential can be mapped back to its owner
+type webauthnUser struct {
+	user        model.User
+	credentials []model.UserCredential
+}
+
+func (u webauthnUser) WebAuthnID() []byte {
+	return []byte(strconv.FormatUint(uint64(u.user.ID), 10))
+}
+
+func (u webauthnUser) WebAuthnName() string {
+	return u.user.Email
+}
+
+func (u webauthnUser) WebAuthnDisplayName() string {
+	return u.user.Name
+}
+
+func (u webauthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := []webauthn.Credential{}
+	for _, userCredential := range u.credentials {
+		credentialID, err := base64.RawURLEncoding.DecodeString(userCredential.CredentialID)
+		if err != nil {
+			continue
+		}
+
+		transports := []protocol.AuthenticatorTransport{}
+		for _, transport := range userCredential.GetTransports() {
+			transports = append(transports, protocol.AuthenticatorTransport(transport))
+		}
+
+		credentials = append(credentials, webauthn.Credential{
+			ID:              credentialID,
+			PublicKey:       userCredential.PublicKey,
+			AttestationType: userCredential.AttestationType,
+			Transport:       transports,
+			Flags: webauthn.CredentialFlags{
+				BackupEligible: userCredential.BackupEligible,
+				BackupState:    userCredential.BackupState,
+			},
+			Authenticator: webauthn.Authenticator{
+				AAGUID:       userCredential.Aaguid,
+				SignCount:    userCredential.SignCount,
+				CloneWarning: userCredential.CloneWarning,
+			},
+		})
+	}
+	return credentials
+}
+
+func (usecase *Usecase) GetWebauthnCredentials(ctx context.Context, req request.GetUserCredentials) (res []response.WebauthnCredential, err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.GetWebauthnCredentials")
+	defer span.Finish()
+
+	userCredentials, err := usecase.repo.GetUserCredentials(ctx, req)
+	if err != nil {
+		return res, err
+	}
+
+	res = []response.WebauthnCredential{}
+	for _, userCredential := range userCredentials {
+		res = append(res, response.NewWebauthnCredential(userCredential))
+	}
+	return res, nil
+}
+
+func (usecase *Usecase) DeleteWebauthnCredential(ctx context.Context, req request.DeleteWebauthnCredential) error {
+	ctx, span := signoz.StartSpan(ctx, "usecase.DeleteWebauthnCredential")
+	defer span.Finish()
+
+	userCredential, err := usecase.repo.GetUserCredential(ctx, request.GetUserCredential{
+		ID:     req.ID,
+		UserID: req.UserID,
+	})
+	if err != nil {
+		return err
+	}
+	if userCredential.ID == 0 {
+		notFoundError := lib.ErrorNotFound
+		notFoundError.Message = "Credential Not Found"
+		return notFoundError
+	}
+
+	return usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		err := usecase.repo.DeleteUserCredential(ctx, userCredential)
+		if err != nil {
+			return err
+		}
+
+		return usecase.recordAuditLog(ctx, model.AuditLog{
+			Action:     model.AuditLogActionPasskeyDelete,
+			TargetType: model.AuditLogTargetUser,
+			TargetID:   userCredential.UserID,
+		}, nil, nil)
+	})
+}
+
+// BeginWebauthnRegistration start the passkey registration ceremony for an authenticated user,
+// credentials the user already owns are excluded so the same authenticator is not registered twice
+func (usecase *Usecase) BeginWebauthnRegistration(ctx context.Context, req request.BeginWebauthn) (res response.WebauthnCeremony, err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.BeginWebauthnRegistration")
+	defer span.Finish()
+
+	if usecase.webAuthn == nil {
+		return res, errWebauthnNotConfigured()
+	}
+
+	user, err := usecase.getWebauthnUser(ctx, req.UserID)
+	if err != nil {
+		return res, err
+	}
+
+	creation, session, err := usecase.webAuthn.BeginRegistration(user,
+		webauthn.WithExclusions(webauthn.Credentials(user.WebAuthnCredentials()).CredentialDescriptors()),
+		webauthn.WithAuthenticatorSelection(protocol.AuthenticatorSelection{
+			RequireResidentKey: protocol.ResidentKeyRequired(),
+			ResidentKey:        protocol.ResidentKeyRequirementRequired,
+			UserVerification:   protocol.VerificationPreferred,
+		}),
+	)
+	if err != nil {
+		return res, usecase.handleWebauthnError(ctx, err, "BeginWebauthnRegistration")
+	}
+
+	return usecase.startWebauthnCeremony(ctx, constant.WebauthnCeremonyRegistration, creation, session)
+}
+
+func (usecase *Usecase) FinishWebauthnRegistration(ctx context.Context, req request.FinishWebauthnRegistration) (res response.WebauthnCredential, err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.FinishWebauthnRegistration")
+	defer span.Finish()
+
+	if usecase.webAuthn == nil {
+		return res, errWebauthnNotConfigured()
+	}
+
+	session, err := usecase.repo.PopWebauthnSession(ctx, constant.WebauthnCeremonyRegistration, req.SessionID)
+	if err != nil {
+		return res, err
+	}
+	if session.Challenge == "" {
+		return res, lib.ErrorVerificationInactive
+	}
+
+	user, err := usecase.getWebauthnUser(ctx, req.UserID)
+	if err != nil {
+		return res, err
+	}
+
+	parsedResponse, err := protocol.ParseCredentialCreationResponseBytes(req.Credential)
+	if err != nil {
+		return res, usecase.handleWebauthnError(ctx, err, "FinishWebauthnRegistration")
+	}
+
+	credential, err := usecase.webAuthn.CreateCredential(user, session, parsedResponse)
+	if err != nil {
+		return res, usecase.handleWebauthnError(ctx, err, "FinishWebauthnRegistration")
+	}
+
+	transports := []string{}
+	for _, transport := range credential.Transport {
+		transports = append(transports, string(transport))
+	}
+
+	var userCredential model.UserCredential
+	var recoveryCodes []string
+	err = usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		timeNow := time.Now()
+		var err error
+		userCredential, err = usecase.repo.CreateUserCredential(ctx, model.UserCredential{
+			UserID:          user.user.ID,
+			Name:            req.Name,
+			CredentialID:    base64.RawURLEncoding.EncodeToString(credential.ID),
+			PublicKey:       credential.PublicKey,
+			AttestationType: credential.AttestationType,
+			Transports:      strings.Join(transports, " "),
+			Aaguid:          credential.Authenticator.AAGUID,
+			SignCount:       credential.Authenticator.SignCount,
+			BackupEligible:  credential.Flags.BackupEligible,
+			BackupState:     credential.Flags.BackupState,
+			CreatedAt:       timeNow,
+			UpdatedAt:       timeNow,
+		})
+		if err != nil {
+			return err
+		}
+
+		recoveryCodes, err = usecase.issueRecoveryCodes(ctx, user.user.ID)
+		if err != nil {
+			return err
+		}
+
+		return usecase.recordAuditLog(ctx, model.AuditLog{
+			Action:     model.AuditLogActionPasskeyRegister,
+			TargetType: model.AuditLogTargetUser,
+			TargetID:   user.user.ID,
+		}, nil, nil)
+	})
+	if err != nil {
+		return res, err
+	}
+
+	res = response.NewWebauthnCredential(userCredential)
+	res.RecoveryCodes = recoveryCodes
+	return res, nil
+}
+
+// BeginWebauthnLogin start a passwordless login ceremony, the credential is discovered by the authenticator
+// so the user does not need to be known beforehand
+func (usecase *Usecase) BeginWebauthnLogin(ctx context.Context) (res response.WebauthnCeremony, err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.BeginWebauthnLogin")
+	defer span.Finish()
+
+	if usecase.webAuthn == nil {
+		return res, errWebauthnNotConfigured()
+	}
+
+	assertion, session, err := usecase.webAuthn.BeginDiscoverableLogin(webauthn.WithUserVerification(protocol.VerificationRequired))
+	if err != nil {
+		return res, usecase.handleWebauthnError(ctx, err, "BeginWebauthnLogin")
+	}
+
+	return usecase.startWebauthnCeremony(ctx, constant.WebauthnCeremonyLogin, assertion, session)
+}
+
+// FinishWebauthnLogin issue a session for a verified passkey assertion, user verification is required
+// during the ceremony so the passkey already satisfies mfa on its own
+func (usecase *Usecase) FinishWebauthnLogin(ctx context.Context, req request.FinishWebauthnLogin) (res response.Auth, err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.FinishWebauthnLogin")
+	defer span.Finish()
+
+	if usecase.webAuthn == nil {
+		return res, errWebauthnNotConfigured()
+	}
+
+	session, err := usecase.repo.PopWebauthnSession(ctx, constant.WebauthnCeremonyLogin, req.SessionID)
+	if err != nil {
+		return res, err
+	}
+	if session.Challenge == "" {
+		return res, lib.ErrorVerificationInactive
+	}
+
+	parsedResponse, err := protocol.ParseCredentialRequestResponseBytes(req.Credential)
+	if err != nil {
+		return res, usecase.handleWebauthnError(ctx, err, "FinishWebauthnLogin")
+	}
+
+	discoveredUser, credential, err := usecase.webAuthn.ValidatePasskeyLogin(func(rawID, userHandle []byte) (webauthn.User, error) {
+		userCredential, err := usecase.repo.GetUserCredential(ctx, request.GetUserCredential{
+			CredentialID: base64.RawURLEncoding.EncodeToString(rawID),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if userCredential.ID == 0 || string(userHandle) != strconv.FormatUint(uint64(userCredential.UserID), 10) {
+			return nil, errors.New("credential not found")
+		}
+
+		return usecase.getWebauthnUser(ctx, userCredential.UserID)
+	}, session, parsedResponse)
+	if err != nil {
+		return res, usecase.handleWebauthnError(ctx, err, "FinishWebauthnLogin")
+	}
+	user := discoveredUser.(webauthnUser)
+
+	err = usecase.checkLoginLockout(ctx, user.user.Email)
+	if err != nil {
+		return res, err
+	}
+
+	err = usecase.checkWebauthnCloneWarning(ctx, user, credential)
+	if err != nil {
+		return res, err
+	}
+
+	var auth model.UserAuth
+	err = usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		err = usecase.updateWebauthnCredential(ctx, user, credential)
+		if err != nil {
+			return err
+		}
+
+		auth, _, err = usecase.generateAuth(ctx, user.user, false)
+		if err != nil {
+			return err
+		}
+
+		return usecase.recordAuditLog(ctx, model.AuditLog{
+			ActorID:    user.user.ID,
+			Action:     model.AuditLogActionLogin,
+			TargetType: model.AuditLogTargetUser,
+			TargetID:   user.user.ID,
+		}, nil, nil)
+	})
+	if err != nil {
+		return res, err
+	}
+
+	return response.NewAuth(auth, user.user, false), nil
+}
+
+// BeginWebauthnMfa start an assertion ceremony limited to the passkeys of the user holding the mfa token
+func (usecase *Usecase) BeginWebauthnMfa(ctx context.Context, req request.BeginWebauthn) (res response.WebauthnCeremony, err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.BeginWebauthnMfa")
+	defer span.Finish()
+
+	if usecase.webAuthn == nil {
+		return res, errWebauthnNotConfigured()
+	}
+
+	user, err := usecase.getWebauthnUser(ctx, req.UserID)
+	if err != nil {
+		return res, err
+	}
+	if len(user.credentials) == 0 {
+		notFoundError := lib.ErrorNotFound
+		notFoundError.Message = "Credential Not Found"
+		return res, notFoundError
+	}
+
+	assertion, session, err := usecase.webAuthn.BeginLogin(user)
+	if err != nil {
+		return res, usecase.handleWebauthnError(ctx, err, "BeginWebauthnMfa")
+	}
+
+	return usecase.startWebauthnCeremony(ctx, constant.WebauthnCeremonyMfa, assertion, session)
+}
+
+// FinishWebauthnMfa exchange an mfa token for a full session, same as ValidateOtp but with a passkey assertion
+func (usecase *Usecase) FinishWebauthnMfa(ctx context.Context, req request.FinishWebauthnLogin) (res response.Auth, err error) {
+	ctx, span := signoz.StartSpan(ctx, "usecase.FinishWebauthnMfa")
+	defer span.Finish()
+
+	if usecase.webAuthn == nil {
+		return res, errWebauthnNotConfigured()
+	}
+
+	session, err := usecase.repo.PopWebauthnSession(ctx, constant.WebauthnCeremonyMfa, req.SessionID)
+	if err != nil {
+		return res, err
+	}
+	if session.Challenge == "" {
+		return res, lib.ErrorVerificationInactive
+	}
+
+	user, err := usecase.getWebauthnUser(ctx, req.UserID)
+	if err != nil {
+		return res, err
+	}
+
+	parsedResponse, err := protocol.ParseCredentialRequestResponseBytes(req.Credential)
+	if err != nil {
+		return res, usecase.handleWebauthnError(ctx, err, "FinishWebauthnMfa")
+	}
+
+	credential, err := usecase.webAuthn.ValidateLogin(user, session, parsedResponse)
+	if err != nil {
+		auditErr := usecase.recordAuditLog(ctx, model.AuditLog{
+			ActorID:    user.user.ID,
+			Action:     model.AuditLogActionMfaValidateFailed,
+			TargetType: model.AuditLogTargetUser,
+			TargetID:   user.user.ID,
+		}, nil, nil)
+		if auditErr != nil {
+			return res, auditErr
+		}
+		return res, usecase.handleWebauthnError(ctx, err, "FinishWebauthnMfa")
+	}
+
+	err = usecase.checkWebauthnCloneWarning(ctx, user, credential)
+	if err != nil {
+		return res, err
+	}
+
+	var auth model.UserAuth
+	err = usecase.repo.Transaction(ctx, func(ctx context.Context) error {
+		err = usecase.updateWebauthnCredential(ctx, user, credential)
+		if err != nil {
+			return err
+		}
+
+		// Generate non-mfa auth
+		auth, _, err = usecase.generateAuth(ctx, user.user, false)
+		if err != nil {
+			return err
+		}
+
+		err = usecase.repo.SetMfaFlag(ctx, user.user.ID)
+		if err != nil {
+			return err
+		}
+
+		return usecase.recordAuditLog(ctx, model.AuditLog{
+			ActorID:    user.user.ID,
+			Action:     model.AuditLogActionMfaValidate,
+			TargetType: model.AuditLogTargetUser,
+			TargetID:   user.user.ID,
+		}, nil, nil)
+	})
+	if err != nil {
+		return res, err
+	}
+
+	return response.NewAuth(auth, user.user, false), nil
+}
+
+func (usecase *Usecase) getWebauthnUser(ctx context.Context, userId uint) (webauthnUser, error) {
+	user, err := usecase.repo.GetUser(ctx, request.GetUser{
+		ID: userId,
+	})
+	if err != nil {
+		return webauthnUser{}, err
+	}
+	if user.ID == 0 {
+		notFoundError := lib.ErrorNotFound
+		notFoundError.Message = "User Not Found"
+		return webauthnUser{}, notFoundError
+	}
+
+	userCredentials, err := usecase.repo.GetUserCredentials(ctx, request.GetUserCredentials{
+		UserID: user.ID,
+	})
+	if err != nil {
+		return webauthnUser{}, err
+	}
+
+	return webauthnUser{user: user, credentials: userCredentials}, nil
+}
+
+func (usecase *Usecase) startWebauthnCeremony(ctx context.Context, ceremony string, options any, session *webauthn.SessionData) (res response.WebauthnCeremony, err error) {
+	sessionId := lib.GenerateUUID()
+	err = usecase.repo.SetWebauthnSession(ctx, ceremony, sessionId, *session)
+	if err != nil {
+		return res, err
+	}
+
+	return response.WebauthnCeremony{
+		SessionID: sessionId,
+		Options:   options,
+	}, nil
+}
+
+// updateWebauthnCredential persist the sign counter and flags returned by a successful assertion,
+// a cloned credential never reaches it since checkWebauthnCloneWarning reject the assertion first
+func (usecase *Usecase) updateWebauthnCredential(ctx context.Context, user webauthnUser, credential *webauthn.Credential) error {
+	credentialID := base64.RawURLEncoding.EncodeToString(credential.ID)
+	for _, userCredential := range user.credentials {
+		if userCredential.CredentialID != credentialID {
+			continue
+		}
+
+		timeNow := time.Now()
+		userCredential.SignCount = credential.Authenticator.SignCount
+		userCredential.BackupState = credential.Flags.BackupState
+		userCredential.LastUsedAt = &timeNow
+		userCredential.UpdatedAt = timeNow
+		_, err := usecase.repo.UpdateUserCredential(ctx, userCredential)
+		return err
+	}
+
+	return nil
+}
+
+// checkWebauthnCloneWarning reject the assertion of a credential whose sign counter did not increase, the authenticator
+// may be cloned. The warning is stored the first time it is raised and the credential stays rejected until the user
+// delete it, otherwise the next attempt of the clone would succeed
+func (usecase *Usecase) checkWebauthnCloneWarning(ctx context.Context, user webauthnUser, credential *webauthn.Credential) error {
+	if !credential.Authenticator.CloneWarning {
+		return nil
+	}
+
+	credentialID := base64.RawURLEncoding.EncodeToString(credential.ID)
+	for _, userCredential := range user.credentials {
+		if userCredential.CredentialID != credentialID || userCredential.CloneWarning {
+			continue
+		}
+
+		logger.LogWarn(ctx, "Webauthn credential sign counter did not increase, the authenticator may be cloned", []zap.Field{
+			zap.Uint("user_id", userCredential.UserID),
+			zap.Uint("credential_id", userCredential.ID),
+			zap.Strings("tags", []string{"usecase", "checkWebauthnCloneWarning", "security"}),
+		}...)
+
+		userCredential.CloneWarning = true
+		userCredential.UpdatedAt = time.Now()
+		_, err := usecase.repo.UpdateUserCredential(ctx, userCredential)
+		if err != nil {
+			return err
+		}
+	}
+
+	return lib.ErrorWebauthnCloned
+}
+
+// handleWebauthnError log the protocol error and only expose its details to the client
+func (usecase *Usecase) handleWebauthnError(ctx context.Context, err error, tag string) error {
+	logger.LogError(ctx, "error webauthn ceremony", []zap.Field{
+		zap.Error(err),
+		zap.Strings("tags", []string{"usecase", tag}),
+	}...)
+
+	webauthnError := lib.ErrorWebauthnInvalid
+	var protocolErr *protocol.Error
+	if errors.As(err, &protocolErr) {
+		webauthnError.ErrDetails = map[string]any{
+			"reason": protocolErr.Details,
+		}
+	}
+	return webauthnError
+}
+
+func errWebauthnNotConfigured() error {
+	notFoundError := lib.ErrorNotFound
+	notFoundError.Message = "Webauthn Not Configured"
+	return notFoundError
+}
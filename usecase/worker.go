@@ -3,6 +3,7 @@ package usecase
 import (
 	"app/lib/mailer"
 	"app/lib/signoz"
+	"app/lib/sms"
 	"app/lib/websocket"
 	"app/request"
 	"context"
@@ -20,6 +21,17 @@ func (usecase *Usecase) SendEmail(ctx context.Context, req request.SendEmailPayl
 	})
 }
 
+func (usecase *Usecase) SendSms(ctx context.Context, req request.SendSmsPayload) error {
+	ctx, span := signoz.StartSpan(ctx, "usecase.SendSms")
+	defer span.Finish()
+
+	return usecase.repo.SendSms(ctx, sms.SendMessageParam{
+		To:      req.To,
+		Channel: req.Channel,
+		Message: req.Message,
+	})
+}
+
 func (usecase *Usecase) BroadcastWebsocketMessage(ctx context.Context, message websocket.Message) error {
 	ctx, span := signoz.StartSpan(ctx, "usecase.BroadcastWebsocketMessage")
 	defer span.Finish()
@@ -0,0 +1,28 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+
+	"app/lib/signoz"
+	"app/request"
+
+	"github.com/hibiken/asynq"
+)
+
+func (w *Worker) WorkerSendSms(ctx context.Context, t *asynq.Task) error {
+	ctx, span := signoz.StartSpan(ctx, "usecase.WorkerSendSms")
+	defer span.Finish()
+
+	var p request.SendSmsPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return err
+	}
+
+	err := w.App.Usecase.SendSms(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
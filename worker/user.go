@@ -0,0 +1,62 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+
+	"app/lib/signoz"
+	"app/request"
+
+	"github.com/hibiken/asynq"
+)
+
+func (w *Worker) WorkerEraseUser(ctx context.Context, t *asynq.Task) error {
+	ctx, span := signoz.StartSpan(ctx, "usecase.WorkerEraseUser")
+	defer span.Finish()
+
+	var p request.EraseUserPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return err
+	}
+
+	err := w.App.Usecase.EraseUser(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (w *Worker) WorkerImportUsers(ctx context.Context, t *asynq.Task) error {
+	ctx, span := signoz.StartSpan(ctx, "usecase.WorkerImportUsers")
+	defer span.Finish()
+
+	var p request.ImportUsersPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return err
+	}
+
+	err := w.App.Usecase.ProcessImportUsers(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (w *Worker) WorkerExportUsers(ctx context.Context, t *asynq.Task) error {
+	ctx, span := signoz.StartSpan(ctx, "usecase.WorkerExportUsers")
+	defer span.Finish()
+
+	var p request.ExportUsersPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return err
+	}
+
+	err := w.App.Usecase.ProcessExportUsers(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	"app"
+	"app/lib/constant"
 	"app/lib/logger"
 
 	"github.com/hibiken/asynq"
@@ -21,8 +23,16 @@ func NewWorker(a *app.App) Worker {
 }
 
 func (s *Worker) RegisterWorker(mux *asynq.ServeMux, taskType, taskName string, skipRetry bool, fn func(ctx context.Context, t *asynq.Task) error) {
+	isJob := slices.Contains(constant.JobTaskTypes, taskType)
 	mux.HandleFunc(taskType, func(ctx context.Context, t *asynq.Task) (err error) {
-		ctx = context.WithValue(ctx, logger.CtxProcessID, t.ResultWriter().TaskID())
+		taskId := t.ResultWriter().TaskID()
+		ctx = context.WithValue(ctx, logger.CtxProcessID, taskId)
+		// Registered before the recover below, so it also sees the error of a panic
+		defer func() {
+			if isJob {
+				s.finishJob(ctx, taskId, taskName, err, skipRetry)
+			}
+		}()
 		defer func() {
 			if r := recover(); r != nil {
 				panicErr := handleWorkerPanic(ctx, taskName, r)
@@ -39,6 +49,9 @@ func (s *Worker) RegisterWorker(mux *asynq.ServeMux, taskType, taskName string,
 			zap.Strings("tags", []string{"worker", taskName}),
 		}...)
 
+		if isJob {
+			s.startJob(ctx, taskId, taskName)
+		}
 		err = fn(ctx, t)
 		if err != nil {
 			logger.LogError(ctx, "process task error", []zap.Field{
@@ -58,6 +71,35 @@ func (s *Worker) RegisterWorker(mux *asynq.ServeMux, taskType, taskName string,
 	})
 }
 
+// startJob mark the job of the task as running, a failed update is only logged so the task is still processed
+func (s *Worker) startJob(ctx context.Context, taskId, taskName string) {
+	err := s.App.Usecase.StartJob(ctx, taskId)
+	if err != nil {
+		logger.LogError(ctx, "failed start job", []zap.Field{
+			zap.Error(err),
+			zap.Strings("tags", []string{"worker", taskName}),
+		}...)
+	}
+}
+
+// finishJob mark the job of the task as completed or failed, the job is retrying while asynq still retry the task
+func (s *Worker) finishJob(ctx context.Context, taskId, taskName string, processErr error, skipRetry bool) {
+	willRetry := false
+	if processErr != nil && !skipRetry {
+		retried, _ := asynq.GetRetryCount(ctx)
+		maxRetry, _ := asynq.GetMaxRetry(ctx)
+		willRetry = retried < maxRetry
+	}
+
+	err := s.App.Usecase.FinishJob(ctx, taskId, processErr, willRetry)
+	if err != nil {
+		logger.LogError(ctx, "failed finish job", []zap.Field{
+			zap.Error(err),
+			zap.Strings("tags", []string{"worker", taskName}),
+		}...)
+	}
+}
+
 func handleWorkerPanic(ctx context.Context, taskName string, panicValue any) (err error) {
 	var errorMsg string
 	switch err := panicValue.(type) {